@@ -62,9 +62,11 @@ func main() {
 
 	apiGroup := router.Group("/api")
 
+	var systemdHandler *api.SystemdHandler
 	enableSystemd := os.Getenv("ENABLE_SYSTEMD")
 	if enableSystemd != "false" {
-		systemdHandler, err := api.NewSystemdHandler(logger)
+		var err error
+		systemdHandler, err = api.NewSystemdHandler(logger)
 		if err != nil {
 			logger.Error("failed to create systemd handler", "error", err)
 			os.Exit(1)
@@ -74,9 +76,11 @@ func main() {
 		systemdHandler.RegisterRoutes(systemdGroup)
 	}
 
+	var containerHandler *api.ContainerHandler
 	enableContainer := os.Getenv("ENABLE_CONTAINER")
 	if enableContainer != "false" {
-		containerHandler, err := api.NewContainerHandler(logger)
+		var err error
+		containerHandler, err = api.NewContainerHandler(logger)
 		if err != nil {
 			logger.Error("failed to create container handler", "error", err)
 			os.Exit(1)
@@ -86,6 +90,12 @@ func main() {
 		containerHandler.RegisterRoutes(containerGroup)
 	}
 
+	if systemdHandler != nil || containerHandler != nil {
+		logsHandler := api.NewLogsHandler(logger, systemdHandler, containerHandler)
+		logsGroup := apiGroup.Group("/logs")
+		logsHandler.RegisterRoutes(logsGroup)
+	}
+
 	docs.SwaggerInfo.Host = addr
 	apiGroup.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
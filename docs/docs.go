@@ -136,9 +136,68 @@ const docTemplate = `{
                 }
             }
         },
+        "/container/{id}/kill": {
+            "post": {
+                "description": "Send a signal to the main process of a container",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "containers"
+                ],
+                "summary": "Kill container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Container ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signal to send",
+                        "name": "signal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ContainerKillRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/container/{id}/logs": {
             "get": {
-                "description": "Stream logs from a container (always includes real-time updates)",
+                "description": "Stream logs from a container. New lines are streamed in real time unless until is given.",
                 "produces": [
                     "text/event-stream"
                 ],
@@ -161,6 +220,30 @@ const docTemplate = `{
                         "description": "Number of historical log lines to return before streaming new ones",
                         "name": "lines",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or before this RFC3339 time or duration ago; disables following",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -170,6 +253,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/SSEvent"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -185,16 +274,19 @@ const docTemplate = `{
                 }
             }
         },
-        "/container/{id}/restart": {
-            "post": {
-                "description": "Restart a container",
+        "/container/{id}/logs/export": {
+            "get": {
+                "description": "Download all log lines of a container matching the filters. Exports are streamed; large ones are gzip-compressed unless compression is none.",
                 "produces": [
-                    "application/json"
+                    "text/plain",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "application/gzip"
                 ],
                 "tags": [
                     "containers"
                 ],
-                "summary": "Restart container",
+                "summary": "Export container logs",
                 "parameters": [
                     {
                         "type": "string",
@@ -202,13 +294,67 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "default": "text",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "auto",
+                            "gzip",
+                            "none"
+                        ],
+                        "type": "string",
+                        "default": "auto",
+                        "description": "Compression of the download; auto compresses exports larger than 1 MiB",
+                        "name": "compression",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or before this RFC3339 time or duration ago",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Message"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -226,16 +372,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/container/{id}/start": {
-            "post": {
-                "description": "Start a container",
+        "/container/{id}/logs/history": {
+            "get": {
+                "description": "Get a page of log lines of a container. Without a cursor the latest lines are returned; pass the before or after cursor of a page to get the lines preceding or following it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "containers"
                 ],
-                "summary": "Start container",
+                "summary": "Get container log history",
                 "parameters": [
                     {
                         "type": "string",
@@ -243,13 +389,64 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of lines to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of a page to return the lines preceding it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of a page to return the lines following it",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines at or before this RFC3339 time or duration ago",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Message"
+                            "$ref": "#/definitions/LogPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -267,16 +464,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/container/{id}/stop": {
+        "/container/{id}/restart": {
             "post": {
-                "description": "Stop a container",
+                "description": "Restart a container",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "containers"
                 ],
-                "summary": "Stop container",
+                "summary": "Restart container",
                 "parameters": [
                     {
                         "type": "string",
@@ -308,21 +505,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd": {
-            "get": {
-                "description": "Get a list of all systemd services",
+        "/container/{id}/start": {
+            "post": {
+                "description": "Start a container",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "systemd"
+                    "containers"
+                ],
+                "summary": "Start container",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Container ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List systemd services",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SystemdServiceList"
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
@@ -334,21 +546,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd/{name}": {
-            "get": {
-                "description": "Get detailed information about a specific systemd service",
+        "/container/{id}/stop": {
+            "post": {
+                "description": "Stop a container",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "systemd"
+                    "containers"
                 ],
-                "summary": "Get systemd service details",
+                "summary": "Stop container",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Service name",
-                        "name": "name",
+                        "description": "Container ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -357,7 +569,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SystemdServiceDetails"
+                            "$ref": "#/definitions/Message"
                         }
                     },
                     "404": {
@@ -375,44 +587,122 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd/{name}/logs": {
+        "/logs": {
             "get": {
-                "description": "Stream logs from a systemd service (always includes real-time updates)",
+                "description": "Stream the logs of several systemd units and containers merged into one stream ordered by time. Every entry is tagged with its source. Entries are held back briefly to put them in order.",
                 "produces": [
                     "text/event-stream"
                 ],
                 "tags": [
-                    "systemd",
+                    "logs",
                     "sse"
                 ],
-                "summary": "Stream service logs",
+                "summary": "Stream merged logs",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Unit names (e.g. nginx or backup.timer)",
+                        "name": "unit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Container IDs",
+                        "name": "container",
+                        "in": "query"
                     },
                     {
                         "type": "integer",
                         "default": 100,
-                        "description": "Number of historical log lines to return before streaming new ones",
+                        "description": "Number of historical log lines per source to return before streaming new ones",
                         "name": "lines",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or before this RFC3339 time or duration ago; disables following",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum priority of unit entries (0-7 or emerg, alert, crit, err, warning, notice, info, debug)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Boot offset (0 current, -1 previous) or boot ID of unit entries",
+                        "name": "boot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope of the units",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SSEvent"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SSEvent"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "content": {
+                                            "$ref": "#/definitions/MergedLogEntry"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/SSEvent"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
@@ -424,34 +714,121 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd/{name}/restart": {
-            "post": {
-                "description": "Restart a systemd service",
+        "/systemd": {
+            "get": {
+                "description": "Get a list of systemd units, filtered by unit type",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "systemd"
                 ],
-                "summary": "Restart service",
+                "summary": "List systemd services",
                 "parameters": [
                     {
+                        "enum": [
+                            "all",
+                            "service",
+                            "socket",
+                            "target",
+                            "device",
+                            "mount",
+                            "automount",
+                            "swap",
+                            "timer",
+                            "path",
+                            "slice",
+                            "scope"
+                        ],
                         "type": "string",
-                        "description": "Service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/Message"
+                        "default": "service",
+                        "description": "Unit type to list, or 'all' for every type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdServiceList"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Render a service (and optional timer) from a structured spec to /etc/systemd/system, verify it, reload systemd and optionally enable and start it. The files are removed again if enabling or starting fails. When started, the response contains the start job, which can be followed at /systemd/jobs/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Create service",
+                "parameters": [
+                    {
+                        "description": "Service definition",
+                        "name": "spec",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitSpec"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitCreated"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -465,30 +842,53 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd/{name}/start": {
-            "post": {
-                "description": "Start a systemd service",
+        "/systemd/analyze": {
+            "get": {
+                "description": "Get the activation time of every unit, slowest first, and the critical chain to a target, like systemd-analyze blame and critical-chain. Activation times are computed from the InactiveExitTimestamp, ActiveEnterTimestamp and ExecMainStartTimestamp properties.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "systemd"
                 ],
-                "summary": "Start service",
+                "summary": "Analyze boot performance",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Service name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "default": "default.target",
+                        "description": "Target the critical chain leads to",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Message"
+                            "$ref": "#/definitions/SystemdBootAnalysis"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -506,30 +906,262 @@ const docTemplate = `{
                 }
             }
         },
-        "/systemd/{name}/stop": {
-            "post": {
-                "description": "Stop a systemd service",
+        "/systemd/boots": {
+            "get": {
+                "description": "List the boots recorded in the journal with the times of their first and last entry, like journalctl --list-boots. Their index or ID can be used as the boot filter of the log endpoints.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "systemd"
                 ],
-                "summary": "Stop service",
+                "summary": "List boots",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdBootList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/events": {
+            "get": {
+                "description": "Stream an event whenever the load, active or sub state of a unit changes. Events are pushed from systemd D-Bus signals.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Stream unit state changes",
                 "parameters": [
                     {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
                         "type": "string",
-                        "description": "Service name",
-                        "name": "name",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SSEvent"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "content": {
+                                            "$ref": "#/definitions/SystemdUnitEvent"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/failed": {
+            "get": {
+                "description": "Get all failed units with their result, the exit status of the main process, the number of restarts, when they failed and the last log entries of the failed run, most recent failure first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "List failed units",
+                "parameters": [
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdFailedUnitList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/jobs": {
+            "get": {
+                "description": "List the jobs queued in systemd and the recently finished jobs started through Sirberus",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "List jobs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJobList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/jobs/{id}": {
+            "get": {
+                "description": "Stream the state of a job whenever it changes. The stream ends with the finished job and its result.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Stream job progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Message"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SSEvent"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "content": {
+                                            "$ref": "#/definitions/SystemdJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "404": {
@@ -545,242 +1177,2912 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "Container": {
-            "type": "object",
-            "properties": {
-                "cpuUsage": {
-                    "description": "CPU usage as percentage of a single core (can exceed 100% if using multiple cores)",
-                    "type": "number"
-                },
-                "id": {
-                    "description": "Short container ID",
+            },
+            "delete": {
+                "description": "Cancel a queued or running systemd job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Cancel job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/journal": {
+            "get": {
+                "description": "Stream the journal of the whole system, or only kernel messages like journalctl -k. New entries are streamed in real time unless until or an earlier boot is selected.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "systemd",
+                    "sse"
+                ],
+                "summary": "Stream system journal",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only kernel messages; defaults to the current boot",
+                        "name": "kernel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Syslog identifiers of the messages (e.g. kernel or sshd)",
+                        "name": "identifier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of historical log lines to return before streaming new ones",
+                        "name": "lines",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or before this RFC3339 time or duration ago; disables following",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots",
+                        "name": "boot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/SSEvent"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "content": {
+                                            "$ref": "#/definitions/LogEntry"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/run": {
+            "post": {
+                "description": "Run a command as a transient service or scope with resource limits, like systemd-run, and stream its output. The final status is sent as an \"exit\" event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "systemd",
+                    "sse"
+                ],
+                "summary": "Run transient unit",
+                "parameters": [
+                    {
+                        "description": "Command to run",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemdRunRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/security": {
+            "get": {
+                "description": "Get the sandboxing exposure of every loaded service, most exposed first, to prioritize which services to harden. The exposure is computed like systemd-analyze security.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get security report",
+                "parameters": [
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdSecurityReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/timers": {
+            "get": {
+                "description": "Get a list of all systemd timers with their next and last elapse times",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "List systemd timers",
+                "parameters": [
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdTimerList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}": {
+            "get": {
+                "description": "Get detailed information about a specific systemd service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get systemd service details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdServiceDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop, disable and remove a service created through Sirberus, including its timer. Waits until the stop jobs have finished, which may take up to the TimeoutStopSec of the units. Fails if a stop job has not finished shortly after that.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Delete service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/dependencies": {
+            "get": {
+                "description": "Get the dependency graph of a systemd unit with the state of every unit. Forward traversal follows Requires, Wants, BindsTo, PartOf and After; reverse traversal follows RequiredBy, WantedBy, BoundBy, ConsistsOf and Before.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get service dependencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "forward",
+                            "reverse"
+                        ],
+                        "type": "string",
+                        "default": "forward",
+                        "description": "Traversal direction",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 2,
+                        "description": "Maximum traversal depth",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdDependencyGraph"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/disable": {
+            "post": {
+                "description": "Disable a systemd service so it no longer starts at boot",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Disable service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitFileChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/enable": {
+            "post": {
+                "description": "Enable a systemd service so it starts at boot",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Enable service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitFileChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/kill": {
+            "post": {
+                "description": "Send a signal to the main, control or all processes of a systemd service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Kill service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signal to send",
+                        "name": "signal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemdKillRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/logs": {
+            "get": {
+                "description": "Stream logs from a systemd service. New entries are streamed in real time unless until or an earlier boot is selected.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "systemd",
+                    "sse"
+                ],
+                "summary": "Stream service logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of historical log lines to return before streaming new ones",
+                        "name": "lines",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or before this RFC3339 time or duration ago; disables following",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots",
+                        "name": "boot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/SSEvent"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/logs/export": {
+            "get": {
+                "description": "Download all journal entries of a systemd unit matching the filters. Exports are streamed; large ones are gzip-compressed unless compression is none.",
+                "produces": [
+                    "text/plain",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "application/vnd.fdo.journal",
+                    "application/gzip"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Export service logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "ndjson",
+                            "csv",
+                            "export"
+                        ],
+                        "type": "string",
+                        "default": "text",
+                        "description": "File format; export is the journal export format with all fields and does not support grep",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "auto",
+                            "gzip",
+                            "none"
+                        ],
+                        "type": "string",
+                        "default": "auto",
+                        "description": "Compression of the download; auto compresses exports larger than 1 MiB",
+                        "name": "compression",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or before this RFC3339 time or duration ago",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots",
+                        "name": "boot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/logs/history": {
+            "get": {
+                "description": "Get a page of journal entries of a systemd unit. Without a cursor the latest entries are returned; pass the before or after cursor of a page to get the entries preceding or following it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get service log history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum number of entries to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of a page to return the entries preceding it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of a page to return the entries following it",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this RFC3339 time or duration ago (e.g. 1h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or before this RFC3339 time or duration ago",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots",
+                        "name": "boot",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Regular expression the message has to match",
+                        "name": "grep",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Match grep case-insensitively",
+                        "name": "ignoreCase",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/LogPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/mask": {
+            "post": {
+                "description": "Mask a systemd service so it cannot be started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Mask service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitFileChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/overrides/{override}": {
+            "put": {
+                "description": "Create or update a drop-in override in /etc/systemd/system/\u003cunit\u003e.d, verify the unit and reload systemd. The override is verified before it is installed and left unchanged if verification fails.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Write drop-in override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Drop-in name (e.g. override or 10-limits.conf)",
+                        "name": "override",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Drop-in content",
+                        "name": "content",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemdOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a drop-in override from /etc/systemd/system/\u003cunit\u003e.d, verify the unit and reload systemd. The unit is verified without the override before it is deleted, which is refused if verification fails.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Delete drop-in override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Drop-in name (e.g. override or 10-limits.conf)",
+                        "name": "override",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/properties": {
+            "post": {
+                "description": "Change resource properties (CPUQuota, MemoryMax, MemoryHigh, TasksMax, IOWeight, AllowedCPUs) of a running systemd service. Changes are lost on reboot unless they are marked persistent. If the persistent changes fail after the runtime ones were applied, the error says so.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Set service properties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Property changes",
+                        "name": "properties",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/SystemdSetPropertiesRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/reload": {
+            "post": {
+                "description": "Reload the configuration of a systemd service without restarting it. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Reload service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/reload-or-restart": {
+            "post": {
+                "description": "Reload a systemd service if it supports reloading, otherwise restart it. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Reload or restart service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/reset-failed": {
+            "post": {
+                "description": "Reset the failed state and restart counter of a systemd service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Reset failed service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/restart": {
+            "post": {
+                "description": "Restart a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Restart service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/security": {
+            "get": {
+                "description": "Assess the sandboxing of a systemd service like systemd-analyze security. Every hardening setting, such as ProtectSystem, PrivateTmp, NoNewPrivileges, CapabilityBoundingSet and RestrictAddressFamilies, is checked and weighted into an exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get service security",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service name (e.g. nginx)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdSecurityAssessment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/start": {
+            "post": {
+                "description": "Start a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Start service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/stop": {
+            "post": {
+                "description": "Stop a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Stop service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/trigger": {
+            "post": {
+                "description": "Start the unit activated by a systemd timer immediately without waiting for its schedule. Returns the queued start job of that unit, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Trigger timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Timer name (e.g. backup or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/try-restart": {
+            "post": {
+                "description": "Restart a systemd service only if it is currently running. Returns the queued job, which can be followed at /systemd/jobs/{id}",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Try-restart service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "replace",
+                            "fail",
+                            "isolate",
+                            "ignore-dependencies"
+                        ],
+                        "type": "string",
+                        "default": "replace",
+                        "description": "Job mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/unit-file": {
+            "get": {
+                "description": "Get the unit file and its drop-in overrides in the order they are applied, like systemctl cat",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Get unit file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitFile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/systemd/{name}/unmask": {
+            "post": {
+                "description": "Unmask a systemd service so it can be started again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "systemd"
+                ],
+                "summary": "Unmask service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unit name (e.g. nginx or backup.timer)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "system",
+                            "user"
+                        ],
+                        "type": "string",
+                        "default": "system",
+                        "description": "Systemd manager scope",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "UID of the user manager (defaults to the UID Sirberus runs as)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SystemdUnitFileChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Container": {
+            "type": "object",
+            "properties": {
+                "cpuUsage": {
+                    "description": "CPU usage as percentage of a single core (can exceed 100% if using multiple cores)",
+                    "type": "number"
+                },
+                "id": {
+                    "description": "Short container ID",
+                    "type": "string"
+                },
+                "image": {
+                    "description": "Container image",
+                    "type": "string"
+                },
+                "isRunning": {
+                    "description": "Whether the container is currently running",
+                    "type": "boolean"
+                },
+                "memoryUsage": {
+                    "description": "Memory usage in bytes (only if running)",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Container name",
+                    "type": "string"
+                },
+                "ports": {
+                    "description": "Exposed ports",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Container status information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ContainerStatus"
+                        }
+                    ]
+                },
+                "uptime": {
+                    "description": "Uptime in seconds (time since service was started)",
+                    "type": "integer"
+                }
+            }
+        },
+        "ContainerDetails": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "Command running in the container",
+                    "type": "string"
+                },
+                "container": {
+                    "description": "Basic container information",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Container"
+                        }
+                    ]
+                },
+                "created": {
+                    "description": "Creation time",
+                    "type": "string"
+                },
+                "environment": {
+                    "description": "Container environment variables",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "labels": {
+                    "description": "Container labels",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "mounts": {
+                    "description": "Container mount points",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Mount"
+                    }
+                },
+                "networks": {
+                    "description": "Container network configurations",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/NetworkConfig"
+                    }
+                },
+                "size": {
+                    "description": "Container size",
+                    "type": "string"
+                }
+            }
+        },
+        "ContainerExecRequest": {
+            "type": "object",
+            "properties": {
+                "command": {
+                    "description": "Command to execute",
+                    "type": "string"
+                }
+            }
+        },
+        "ContainerKillRequest": {
+            "type": "object",
+            "properties": {
+                "signal": {
+                    "description": "Signal name (e.g., \"SIGHUP\", \"SIGKILL\")",
+                    "type": "string"
+                }
+            }
+        },
+        "ContainerList": {
+            "type": "object",
+            "properties": {
+                "containers": {
+                    "description": "List of containers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Container"
+                    }
+                },
+                "count": {
+                    "description": "Total count of containers",
+                    "type": "integer"
+                }
+            }
+        },
+        "ContainerStatus": {
+            "type": "object",
+            "properties": {
+                "dead": {
+                    "description": "Whether the container is dead",
+                    "type": "boolean"
+                },
+                "error": {
+                    "description": "Error message if any",
+                    "type": "string"
+                },
+                "exitCode": {
+                    "description": "Exit code if the container has exited",
+                    "type": "integer"
+                },
+                "finishedAt": {
+                    "description": "When the container finished (RFC3339 format)",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Human-readable status message (e.g., \"Up 2 minutes\", \"Exited (0) 5 minutes ago\")",
+                    "type": "string"
+                },
+                "oomKilled": {
+                    "description": "Whether the container was killed due to OOM",
+                    "type": "boolean"
+                },
+                "paused": {
+                    "description": "Whether the container is paused",
+                    "type": "boolean"
+                },
+                "pid": {
+                    "description": "Process ID of the container",
+                    "type": "integer"
+                },
+                "restarting": {
+                    "description": "Whether the container is restarting",
+                    "type": "boolean"
+                },
+                "running": {
+                    "description": "Whether the container is currently running",
+                    "type": "boolean"
+                },
+                "startedAt": {
+                    "description": "When the container was started (RFC3339 format)",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "Container state (e.g., \"created\", \"running\", \"paused\", \"restarting\", \"removing\", \"exited\", \"dead\")",
+                    "type": "string"
+                }
+            }
+        },
+        "ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "LogEntry": {
+            "type": "object",
+            "properties": {
+                "bootId": {
+                    "description": "ID of the boot the entry was logged in",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Journal cursor of the entry",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Log message",
+                    "type": "string"
+                },
+                "pid": {
+                    "description": "ID of the process that logged the entry",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Syslog priority from 0 (emerg) to 7 (debug), omitted if unknown",
+                    "type": "integer"
+                },
+                "syslogIdentifier": {
+                    "description": "Syslog identifier, usually the name of the program",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time of the entry (RFC3339 with microseconds)",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit that logged the entry",
+                    "type": "string"
+                }
+            }
+        },
+        "LogPage": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "Cursor for the page of newer entries",
+                    "type": "string"
+                },
+                "before": {
+                    "description": "Cursor for the page of older entries, empty once the start of the log is reached",
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LogEntry"
+                    }
+                }
+            }
+        },
+        "MergedLogEntry": {
+            "type": "object",
+            "properties": {
+                "bootId": {
+                    "description": "ID of the boot the entry was logged in",
+                    "type": "string"
+                },
+                "cursor": {
+                    "description": "Journal cursor of the entry",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Log message",
+                    "type": "string"
+                },
+                "pid": {
+                    "description": "ID of the process that logged the entry",
+                    "type": "integer"
+                },
+                "priority": {
+                    "description": "Syslog priority from 0 (emerg) to 7 (debug), omitted if unknown",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "Name of the unit or ID of the container the entry comes from",
+                    "type": "string"
+                },
+                "sourceType": {
+                    "description": "Type of the source, \"unit\" or \"container\"",
+                    "type": "string"
+                },
+                "syslogIdentifier": {
+                    "description": "Syslog identifier, usually the name of the program",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time of the entry (RFC3339 with microseconds)",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit that logged the entry",
+                    "type": "string"
+                }
+            }
+        },
+        "Message": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "Mount": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "Destination path in container",
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "Mount mode (ro, rw)",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Source path on host",
+                    "type": "string"
+                }
+            }
+        },
+        "NetworkConfig": {
+            "type": "object",
+            "properties": {
+                "gateway": {
+                    "description": "Network gateway",
+                    "type": "string"
+                },
+                "ipAddress": {
+                    "description": "Network IP address",
+                    "type": "string"
+                },
+                "macAddress": {
+                    "description": "Network MAC address",
+                    "type": "string"
+                }
+            }
+        },
+        "SSEvent": {
+            "type": "object",
+            "properties": {
+                "content": {}
+            }
+        },
+        "SystemdBoot": {
+            "type": "object",
+            "properties": {
+                "firstEntry": {
+                    "description": "Time of the first journal entry of the boot (RFC3339 with microseconds)",
                     "type": "string"
                 },
-                "image": {
-                    "description": "Container image",
+                "id": {
+                    "description": "Boot ID",
                     "type": "string"
                 },
-                "isRunning": {
-                    "description": "Whether the container is currently running",
-                    "type": "boolean"
-                },
-                "memoryUsage": {
-                    "description": "Memory usage in bytes (only if running)",
+                "index": {
+                    "description": "Offset to the current boot: 0 is the current boot, -1 the previous one",
                     "type": "integer"
                 },
-                "name": {
-                    "description": "Container name",
-                    "type": "string"
-                },
-                "ports": {
-                    "description": "Exposed ports",
+                "lastEntry": {
+                    "description": "Time of the last journal entry of the boot (RFC3339 with microseconds)",
                     "type": "string"
+                }
+            }
+        },
+        "SystemdBootAnalysis": {
+            "type": "object",
+            "properties": {
+                "blame": {
+                    "description": "Units that took time to activate, slowest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdUnitTiming"
+                    }
                 },
-                "status": {
-                    "description": "Container status information",
+                "criticalChain": {
+                    "description": "Critical chain to the target",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/ContainerStatus"
+                            "$ref": "#/definitions/types.SystemdCriticalChainNode"
                         }
                     ]
                 },
-                "uptime": {
-                    "description": "Uptime in seconds (time since service was started)",
+                "finished": {
+                    "description": "Whether the boot has finished",
+                    "type": "boolean"
+                },
+                "userspaceTime": {
+                    "description": "Time from the start of userspace until the boot finished, in milliseconds; 0 while booting",
                     "type": "integer"
                 }
             }
         },
-        "ContainerDetails": {
+        "SystemdBootList": {
             "type": "object",
             "properties": {
-                "command": {
-                    "description": "Command running in the container",
+                "boots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdBoot"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SystemdDependencyEdge": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "Unit declaring the dependency",
                     "type": "string"
                 },
-                "container": {
-                    "description": "Basic container information",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/Container"
-                        }
-                    ]
+                "to": {
+                    "description": "Unit the dependency points to",
+                    "type": "string"
                 },
-                "created": {
-                    "description": "Creation time",
+                "type": {
+                    "description": "Dependency type (e.g., \"Requires\", \"WantedBy\", \"After\")",
                     "type": "string"
+                }
+            }
+        },
+        "SystemdDependencyGraph": {
+            "type": "object",
+            "properties": {
+                "depth": {
+                    "description": "Maximum traversal depth",
+                    "type": "integer"
                 },
-                "environment": {
-                    "description": "Container environment variables",
+                "direction": {
+                    "description": "Traversal direction: \"forward\" (what the unit needs) or \"reverse\" (what needs the unit)",
+                    "type": "string"
+                },
+                "edges": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    }
-                },
-                "labels": {
-                    "description": "Container labels",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                        "$ref": "#/definitions/SystemdDependencyEdge"
                     }
                 },
-                "mounts": {
-                    "description": "Container mount points",
+                "nodes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Mount"
-                    }
-                },
-                "networks": {
-                    "description": "Container network configurations",
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/NetworkConfig"
+                        "$ref": "#/definitions/SystemdDependencyNode"
                     }
                 },
-                "size": {
-                    "description": "Container size",
+                "root": {
+                    "description": "Unit the graph was built from",
                     "type": "string"
+                },
+                "truncated": {
+                    "description": "Whether traversal stopped early because the graph grew too large",
+                    "type": "boolean"
                 }
             }
         },
-        "ContainerExecRequest": {
+        "SystemdDependencyNode": {
             "type": "object",
             "properties": {
-                "command": {
-                    "description": "Command to execute",
+                "activeState": {
+                    "description": "Active state (e.g., \"active\", \"inactive\", \"failed\")",
+                    "type": "string"
+                },
+                "depth": {
+                    "description": "Distance from the root unit",
+                    "type": "integer"
+                },
+                "loadState": {
+                    "description": "Load state (e.g., \"loaded\", \"not-found\")",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unit name",
+                    "type": "string"
+                },
+                "subState": {
+                    "description": "Sub state (e.g., \"running\", \"dead\")",
                     "type": "string"
                 }
             }
         },
-        "ContainerList": {
+        "SystemdFailedUnit": {
             "type": "object",
             "properties": {
-                "containers": {
-                    "description": "List of containers",
+                "description": {
+                    "description": "Unit description",
+                    "type": "string"
+                },
+                "execMainCode": {
+                    "description": "How the main process of a service ended: \"exited\", \"killed\" or \"dumped\"",
+                    "type": "string"
+                },
+                "execMainStatus": {
+                    "description": "Exit code of the main process, or the signal number if it was killed",
+                    "type": "integer"
+                },
+                "failedAt": {
+                    "description": "Time the failed run stopped (RFC3339 format), only set if the last run did not succeed",
+                    "type": "string"
+                },
+                "failureLogs": {
+                    "description": "Last log entries of the failed run, including the messages systemd logged about it",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Container"
+                        "$ref": "#/definitions/LogEntry"
                     }
                 },
+                "nRestarts": {
+                    "description": "Number of automatic restarts of a service",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Unit name",
+                    "type": "string"
+                },
+                "result": {
+                    "description": "Result of the last run (e.g., \"success\", \"exit-code\", \"signal\", \"core-dump\", \"timeout\", \"start-limit-hit\")",
+                    "type": "string"
+                },
+                "subState": {
+                    "description": "Sub state of the unit (e.g., \"failed\")",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Unit type (e.g., \"service\", \"timer\", \"mount\")",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdFailedUnitList": {
+            "type": "object",
+            "properties": {
                 "count": {
-                    "description": "Total count of containers",
                     "type": "integer"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdFailedUnit"
+                    }
                 }
             }
         },
-        "ContainerStatus": {
+        "SystemdJob": {
             "type": "object",
             "properties": {
-                "dead": {
-                    "description": "Whether the container is dead",
-                    "type": "boolean"
+                "id": {
+                    "description": "Numeric job ID",
+                    "type": "integer"
                 },
-                "error": {
-                    "description": "Error message if any",
+                "result": {
+                    "description": "Job result once finished (e.g., \"done\", \"failed\", \"canceled\", \"timeout\")",
                     "type": "string"
                 },
-                "exitCode": {
-                    "description": "Exit code if the container has exited",
+                "state": {
+                    "description": "Job state (\"waiting\", \"running\" or \"finished\")",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Job type (e.g., \"start\", \"stop\", \"restart\")",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit the job operates on",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdJobList": {
+            "type": "object",
+            "properties": {
+                "count": {
                     "type": "integer"
                 },
-                "finishedAt": {
-                    "description": "When the container finished (RFC3339 format)",
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdJob"
+                    }
+                }
+            }
+        },
+        "SystemdKillRequest": {
+            "type": "object",
+            "properties": {
+                "signal": {
+                    "description": "Signal name (e.g., \"SIGHUP\", \"SIGKILL\")",
                     "type": "string"
                 },
-                "message": {
-                    "description": "Human-readable status message (e.g., \"Up 2 minutes\", \"Exited (0) 5 minutes ago\")",
+                "target": {
+                    "description": "Processes to signal: \"main\", \"control\" or \"all\" (defaults to \"all\")",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Content of the drop-in file",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdPropertyChange": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "Property name: CPUQuota, MemoryMax, MemoryHigh, TasksMax, IOWeight or AllowedCPUs",
                     "type": "string"
                 },
-                "oomKilled": {
-                    "description": "Whether the container was killed due to OOM",
+                "persistent": {
+                    "description": "Keep the change across reboots; by default it is applied at runtime only, like systemctl set-property --runtime",
                     "type": "boolean"
                 },
-                "paused": {
-                    "description": "Whether the container is paused",
-                    "type": "boolean"
+                "value": {
+                    "description": "New value (e.g., \"50%\", \"512M\", \"infinity\", \"0-3\")",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdResourceLimits": {
+            "type": "object",
+            "properties": {
+                "allowedCPUs": {
+                    "description": "CPUs the unit may run on (e.g., \"0-3,6\")",
+                    "type": "string"
                 },
-                "pid": {
-                    "description": "Process ID of the container",
-                    "type": "integer"
+                "cpuQuota": {
+                    "description": "CPU quota as a percentage of a single core (e.g., \"50%\")",
+                    "type": "string"
                 },
-                "restarting": {
-                    "description": "Whether the container is restarting",
-                    "type": "boolean"
+                "ioWeight": {
+                    "description": "IO weight (1-10000)",
+                    "type": "string"
                 },
-                "running": {
-                    "description": "Whether the container is currently running",
-                    "type": "boolean"
+                "memoryHigh": {
+                    "description": "Memory throttling threshold in bytes",
+                    "type": "string"
                 },
-                "startedAt": {
-                    "description": "When the container was started (RFC3339 format)",
+                "memoryMax": {
+                    "description": "Hard memory limit in bytes",
                     "type": "string"
                 },
-                "state": {
-                    "description": "Container state (e.g., \"created\", \"running\", \"paused\", \"restarting\", \"removing\", \"exited\", \"dead\")",
+                "tasksMax": {
+                    "description": "Maximum number of tasks",
                     "type": "string"
                 }
             }
         },
-        "ErrorResponse": {
+        "SystemdRunRequest": {
             "type": "object",
             "properties": {
-                "error": {
+                "command": {
+                    "description": "Command to execute and its arguments (e.g., [\"sh\", \"-c\", \"echo a b\"])",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cpuQuota": {
+                    "description": "CPU quota as a percentage of a single core (e.g., \"50%\", \"200%\")",
+                    "type": "string"
+                },
+                "memoryMax": {
+                    "description": "Memory limit (e.g., \"512M\", \"2G\" or a number of bytes)",
                     "type": "string"
+                },
+                "runtimeMaxSec": {
+                    "description": "Maximum runtime in seconds before the unit is terminated",
+                    "type": "integer"
+                },
+                "scope": {
+                    "description": "Run the command in a transient scope attached to Sirberus instead of a transient service",
+                    "type": "boolean"
                 }
             }
         },
-        "Message": {
+        "SystemdSecurityAssessment": {
             "type": "object",
             "properties": {
-                "message": {
+                "checks": {
+                    "description": "Assessed settings, most exposing first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdSecurityCheck"
+                    }
+                },
+                "exposure": {
+                    "description": "Overall exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed)",
+                    "type": "number"
+                },
+                "rating": {
+                    "description": "Rating of the exposure: PERFECT, OK, MEDIUM, EXPOSED, UNSAFE or DANGEROUS",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit name",
                     "type": "string"
                 }
             }
         },
-        "Mount": {
+        "SystemdSecurityCheck": {
             "type": "object",
             "properties": {
-                "destination": {
-                    "description": "Destination path in container",
+                "description": {
+                    "description": "What the current value of the setting allows the service",
                     "type": "string"
                 },
-                "mode": {
-                    "description": "Mount mode (ro, rw)",
+                "exposure": {
+                    "description": "Exposure of the setting from 0.0 (hardened) to 1.0 (not hardened)",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Setting the check assesses (e.g. \"ProtectSystem=\" or \"CapabilityBoundingSet=~CAP_SYS_ADMIN\")",
                     "type": "string"
                 },
-                "source": {
-                    "description": "Source path on host",
-                    "type": "string"
+                "passed": {
+                    "description": "Whether the setting is fully hardened",
+                    "type": "boolean"
+                },
+                "weight": {
+                    "description": "Weight of the check in the exposure score",
+                    "type": "integer"
+                }
+            }
+        },
+        "SystemdSecurityReport": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdSecuritySummary"
+                    }
                 }
             }
         },
-        "NetworkConfig": {
+        "SystemdSecuritySummary": {
             "type": "object",
             "properties": {
-                "gateway": {
-                    "description": "Network gateway",
+                "activeState": {
+                    "description": "Active state of the service (e.g., \"active\", \"inactive\", \"failed\")",
                     "type": "string"
                 },
-                "ipAddress": {
-                    "description": "Network IP address",
+                "exposure": {
+                    "description": "Overall exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed)",
+                    "type": "number"
+                },
+                "rating": {
+                    "description": "Rating of the exposure: PERFECT, OK, MEDIUM, EXPOSED, UNSAFE or DANGEROUS",
                     "type": "string"
                 },
-                "macAddress": {
-                    "description": "Network MAC address",
+                "unit": {
+                    "description": "Unit name",
                     "type": "string"
                 }
             }
         },
-        "SSEvent": {
-            "type": "object",
-            "properties": {
-                "content": {}
-            }
-        },
         "SystemdService": {
             "type": "object",
             "properties": {
@@ -797,7 +4099,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "loadState": {
-                    "description": "Load state (e.g., \"loaded\", \"not-found\")",
+                    "description": "Load state (e.g., \"loaded\", \"not-found\", or \"not-loaded\" for installed units systemd has not loaded)",
                     "type": "string"
                 },
                 "memoryUsage": {
@@ -812,6 +4114,14 @@ const docTemplate = `{
                     "description": "Sub state (e.g., \"running\", \"dead\")",
                     "type": "string"
                 },
+                "type": {
+                    "description": "Unit type (e.g., \"service\", \"timer\", \"socket\")",
+                    "type": "string"
+                },
+                "unitFileState": {
+                    "description": "Unit file state (e.g., \"enabled\", \"disabled\", \"masked\", \"static\")",
+                    "type": "string"
+                },
                 "uptime": {
                     "description": "Uptime in seconds (time since service was started)",
                     "type": "integer"
@@ -843,6 +4153,25 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "execMainCode": {
+                    "description": "How the main process of a service ended: \"exited\", \"killed\" or \"dumped\"",
+                    "type": "string"
+                },
+                "execMainStatus": {
+                    "description": "Exit code of the main process, or the signal number if it was killed",
+                    "type": "integer"
+                },
+                "failedAt": {
+                    "description": "Time the failed run stopped (RFC3339 format), only set if the last run did not succeed",
+                    "type": "string"
+                },
+                "failureLogs": {
+                    "description": "Last log entries of the failed run, including the messages systemd logged about it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LogEntry"
+                    }
+                },
                 "fragmentPath": {
                     "description": "Path to the unit file",
                     "type": "string"
@@ -867,6 +4196,14 @@ const docTemplate = `{
                     "description": "Bytes received over IP",
                     "type": "integer"
                 },
+                "limits": {
+                    "description": "Current resource limits",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SystemdResourceLimits"
+                        }
+                    ]
+                },
                 "mainPID": {
                     "description": "Main process ID",
                     "type": "integer"
@@ -879,6 +4216,10 @@ const docTemplate = `{
                     "description": "Peak memory usage in bytes",
                     "type": "integer"
                 },
+                "nRestarts": {
+                    "description": "Number of automatic restarts of a service",
+                    "type": "integer"
+                },
                 "processes": {
                     "description": "List of processes",
                     "type": "array",
@@ -886,6 +4227,18 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "result": {
+                    "description": "Result of the last run (e.g., \"success\", \"exit-code\", \"signal\", \"core-dump\", \"timeout\", \"start-limit-hit\")",
+                    "type": "string"
+                },
+                "security": {
+                    "description": "Sandboxing assessment, only set for services",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SystemdSecurityAssessment"
+                        }
+                    ]
+                },
                 "service": {
                     "$ref": "#/definitions/SystemdService"
                 },
@@ -923,6 +4276,306 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "SystemdSetPropertiesRequest": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdPropertyChange"
+                    }
+                }
+            }
+        },
+        "SystemdTimer": {
+            "type": "object",
+            "properties": {
+                "activeState": {
+                    "description": "Active state (e.g., \"active\", \"inactive\")",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Timer description",
+                    "type": "string"
+                },
+                "lastTrigger": {
+                    "description": "Last time the timer was triggered (RFC3339 format, empty if never triggered)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Timer unit name",
+                    "type": "string"
+                },
+                "nextElapse": {
+                    "description": "Next time the timer elapses (RFC3339 format, empty if not scheduled)",
+                    "type": "string"
+                },
+                "onCalendar": {
+                    "description": "OnCalendar expressions of the timer",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "persistent": {
+                    "description": "Whether missed runs are caught up after downtime",
+                    "type": "boolean"
+                },
+                "unit": {
+                    "description": "Unit activated when the timer elapses",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdTimerList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "timers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdTimer"
+                    }
+                }
+            }
+        },
+        "SystemdTimerSpec": {
+            "type": "object",
+            "properties": {
+                "onCalendar": {
+                    "description": "OnCalendar expressions (e.g., \"daily\", \"*-*-* 03:00:00\")",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "persistent": {
+                    "description": "Whether missed runs are caught up after downtime",
+                    "type": "boolean"
+                }
+            }
+        },
+        "SystemdUnitCreated": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "description": "Start job of the service or its timer, only set if it was started; follow it at /systemd/jobs/{id}",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SystemdJob"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "Name of the created service",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitEvent": {
+            "type": "object",
+            "properties": {
+                "activeState": {
+                    "description": "Active state (e.g., \"active\", \"inactive\", \"failed\")",
+                    "type": "string"
+                },
+                "loadState": {
+                    "description": "Load state (e.g., \"loaded\", \"not-found\")",
+                    "type": "string"
+                },
+                "subState": {
+                    "description": "Sub state (e.g., \"running\", \"dead\")",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "Time the change was received (RFC3339)",
+                    "type": "string"
+                },
+                "unit": {
+                    "description": "Unit name",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitFile": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Main unit file followed by drop-ins in the order they are applied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdUnitFileSource"
+                    }
+                },
+                "name": {
+                    "description": "Unit name",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitFileChange": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "description": "Target of the symlink (empty for removals)",
+                    "type": "string"
+                },
+                "filename": {
+                    "description": "Path of the symlink that was created or removed",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type of change (e.g., \"symlink\", \"unlink\")",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitFileChangeResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Symlink changes made by the operation",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SystemdUnitFileChange"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitFileSource": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "File content",
+                    "type": "string"
+                },
+                "dropIn": {
+                    "description": "Whether the file is a drop-in override rather than the main unit file",
+                    "type": "boolean"
+                },
+                "origin": {
+                    "description": "Origin of the file: \"vendor\" (/usr/lib, /lib), \"admin\" (/etc), \"runtime\" (/run) or \"other\"",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path of the file",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitSpec": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Service description",
+                    "type": "string"
+                },
+                "enable": {
+                    "description": "Whether to enable the unit after creating it",
+                    "type": "boolean"
+                },
+                "environment": {
+                    "description": "Environment variables passed to the service",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "execStart": {
+                    "description": "Command to run",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Service name (e.g., \"myapp\" or \"myapp.service\")",
+                    "type": "string"
+                },
+                "restart": {
+                    "description": "Restart policy (e.g., \"no\", \"on-failure\", \"always\")",
+                    "type": "string"
+                },
+                "start": {
+                    "description": "Whether to start the unit after creating it",
+                    "type": "boolean"
+                },
+                "timer": {
+                    "description": "Optional timer that activates the service on a schedule",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SystemdTimerSpec"
+                        }
+                    ]
+                },
+                "user": {
+                    "description": "User to run the service as",
+                    "type": "string"
+                },
+                "wantedBy": {
+                    "description": "Targets that pull in the service when enabled (defaults to multi-user.target)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "workingDirectory": {
+                    "description": "Working directory of the service",
+                    "type": "string"
+                }
+            }
+        },
+        "SystemdUnitTiming": {
+            "type": "object",
+            "properties": {
+                "activatedAt": {
+                    "description": "Time since userspace started at which the unit became active, in milliseconds",
+                    "type": "integer"
+                },
+                "activationTime": {
+                    "description": "Time from leaving the inactive state until the unit became active, in milliseconds",
+                    "type": "integer"
+                },
+                "execMainStartTime": {
+                    "description": "Time from leaving the inactive state until the main process of a service was started,\nin milliseconds; the rest of the activation time was spent waiting for it to become ready",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Unit name",
+                    "type": "string"
+                }
+            }
+        },
+        "types.SystemdCriticalChainNode": {
+            "type": "object",
+            "properties": {
+                "activatedAt": {
+                    "description": "Time since userspace started at which the unit became active, in milliseconds",
+                    "type": "integer"
+                },
+                "activationTime": {
+                    "description": "Time from leaving the inactive state until the unit became active, in milliseconds",
+                    "type": "integer"
+                },
+                "children": {
+                    "description": "Units that became active last before this unit, usually one; units already shown\nelsewhere in the chain are not expanded again",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.SystemdCriticalChainNode"
+                    }
+                },
+                "execMainStartTime": {
+                    "description": "Time from leaving the inactive state until the main process of a service was started,\nin milliseconds; the rest of the activation time was spent waiting for it to become ready",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Unit name",
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,321 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// LogExportFormat is a file format logs can be downloaded in
+type LogExportFormat string
+
+const (
+	// LogExportText writes one line per entry like journalctl's default output
+	LogExportText LogExportFormat = "text"
+	// LogExportNDJSON writes one JSON log entry per line
+	LogExportNDJSON LogExportFormat = "ndjson"
+	// LogExportCSV writes a header row followed by one row per entry
+	LogExportCSV LogExportFormat = "csv"
+	// LogExportJournal is the journal export format understood by systemd-journal-remote.
+	// It is written by journalctl with WriteRawLogExport and only available for units.
+	LogExportJournal LogExportFormat = "export"
+)
+
+// Compression modes of log exports
+const (
+	LogCompressionAuto = "auto"
+	LogCompressionGzip = "gzip"
+	LogCompressionNone = "none"
+)
+
+// logExportGzipThreshold is the size from which exports are compressed in auto mode
+const logExportGzipThreshold = 1 << 20
+
+// logExportFiles maps the export formats to their content type and file extension
+var logExportFiles = map[LogExportFormat]struct {
+	contentType string
+	extension   string
+}{
+	LogExportText:    {"text/plain; charset=utf-8", ".log"},
+	LogExportNDJSON:  {"application/x-ndjson", ".ndjson"},
+	LogExportCSV:     {"text/csv; charset=utf-8", ".csv"},
+	LogExportJournal: {"application/vnd.fdo.journal", ".export"},
+}
+
+// ParseLogExportParams parses the 'format' and 'compression' query parameters of the log export endpoints
+func ParseLogExportParams(c *gin.Context) (LogExportFormat, string, error) {
+	format := LogExportFormat(c.DefaultQuery("format", string(LogExportText)))
+	if _, ok := logExportFiles[format]; !ok {
+		return "", "", fmt.Errorf("invalid format %q", format)
+	}
+
+	compression := c.DefaultQuery("compression", LogCompressionAuto)
+	switch compression {
+	case LogCompressionAuto, LogCompressionGzip, LogCompressionNone:
+	default:
+		return "", "", fmt.Errorf("invalid compression %q", compression)
+	}
+
+	return format, compression, nil
+}
+
+// csvLogHeader is the header row of CSV exports
+const csvLogHeader = "time,priority,pid,identifier,unit,message\n"
+
+// WriteLogExport streams the entries of logCh to the client as a file download named after id.
+// The response is only started once there is output, so a returned error means nothing has been
+// written yet and the caller still has to respond. Errors after that can only be logged.
+// The journal export format cannot be rebuilt from log entries and is rejected.
+func WriteLogExport(
+	c *gin.Context,
+	id string,
+	format LogExportFormat,
+	compression string,
+	logCh <-chan types.LogEntry,
+	errCh <-chan error,
+	logger *slog.Logger,
+) error {
+	if format == LogExportJournal {
+		return fmt.Errorf("format %q cannot be written from log entries", format)
+	}
+
+	writer := newLogExportWriter(c, id, format, compression)
+	if format == LogExportCSV {
+		// Buffered without starting the response, so empty exports have a header too
+		writer.buffer.WriteString(csvLogHeader)
+	}
+	encoder := newLogEncoder(format, writer)
+
+	count := 0
+	for entry := range logCh {
+		if err := encoder.Encode(entry); err != nil {
+			logger.Warn("failed to write log export",
+				"id", id,
+				"error", err)
+			return nil
+		}
+		count++
+	}
+
+	if err := <-errCh; err != nil {
+		if !writer.started() {
+			return err
+		}
+		// Leave a compressed export without its trailer so the truncation is noticed
+		logger.Error("log export ended early",
+			"id", id,
+			"error", err)
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		logger.Warn("failed to finish log export",
+			"id", id,
+			"error", err)
+		return nil
+	}
+
+	logger.Info("exported logs",
+		"id", id,
+		"entries", count,
+		"compressed", writer.gzip != nil)
+	return nil
+}
+
+// WriteRawLogExport sends the output write produces, already encoded in format, to the client
+// as a file download named after id. Like WriteLogExport, a returned error means nothing has
+// been written yet.
+func WriteRawLogExport(
+	c *gin.Context,
+	id string,
+	format LogExportFormat,
+	compression string,
+	write func(w io.Writer) error,
+	logger *slog.Logger,
+) error {
+	writer := newLogExportWriter(c, id, format, compression)
+
+	if err := write(writer); err != nil {
+		if !writer.started() {
+			return err
+		}
+		logger.Error("log export ended early",
+			"id", id,
+			"error", err)
+		return nil
+	}
+
+	if err := writer.Close(); err != nil {
+		logger.Warn("failed to finish log export",
+			"id", id,
+			"error", err)
+		return nil
+	}
+
+	logger.Info("exported logs",
+		"id", id,
+		"compressed", writer.gzip != nil)
+	return nil
+}
+
+// logExportWriter holds back the response headers until it knows whether the export is
+// compressed. In auto mode output is buffered until it exceeds logExportGzipThreshold;
+// smaller exports are sent as they are once complete.
+type logExportWriter struct {
+	c           *gin.Context
+	filename    string
+	contentType string
+	compression string
+	buffer      bytes.Buffer
+	out         io.Writer
+	gzip        *gzip.Writer
+}
+
+func newLogExportWriter(c *gin.Context, id string, format LogExportFormat, compression string) *logExportWriter {
+	file := logExportFiles[format]
+	return &logExportWriter{
+		c:           c,
+		filename:    fmt.Sprintf("%s-%s%s", id, time.Now().UTC().Format("20060102T150405Z"), file.extension),
+		contentType: file.contentType,
+		compression: compression,
+	}
+}
+
+func (w *logExportWriter) Write(p []byte) (int, error) {
+	if w.started() {
+		return w.out.Write(p)
+	}
+
+	w.buffer.Write(p)
+	switch {
+	case w.compression == LogCompressionNone:
+		return len(p), w.start(false)
+	case w.compression == LogCompressionGzip, w.buffer.Len() >= logExportGzipThreshold:
+		return len(p), w.start(true)
+	}
+	return len(p), nil
+}
+
+// Close sends any buffered output and finishes the compressed stream
+func (w *logExportWriter) Close() error {
+	if !w.started() {
+		if err := w.start(w.compression == LogCompressionGzip); err != nil {
+			return err
+		}
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+func (w *logExportWriter) started() bool {
+	return w.out != nil
+}
+
+// start sends the download headers and the buffered output
+func (w *logExportWriter) start(compress bool) error {
+	filename, contentType := w.filename, w.contentType
+	if compress {
+		filename += ".gz"
+		contentType = "application/gzip"
+	}
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", disposition)
+	w.c.Status(http.StatusOK)
+
+	w.out = w.c.Writer
+	if compress {
+		w.gzip = gzip.NewWriter(w.c.Writer)
+		w.out = w.gzip
+	}
+
+	_, err := w.out.Write(w.buffer.Bytes())
+	w.buffer = bytes.Buffer{}
+	return err
+}
+
+// logEncoder writes log entries in an export format
+type logEncoder interface {
+	Encode(entry types.LogEntry) error
+}
+
+func newLogEncoder(format LogExportFormat, w io.Writer) logEncoder {
+	switch format {
+	case LogExportNDJSON:
+		return ndjsonLogEncoder{json.NewEncoder(w)}
+	case LogExportCSV:
+		return csvLogEncoder{csv.NewWriter(w)}
+	}
+	return textLogEncoder{w}
+}
+
+// textLogEncoder writes entries as "time identifier[pid]: message"
+type textLogEncoder struct {
+	w io.Writer
+}
+
+func (e textLogEncoder) Encode(entry types.LogEntry) error {
+	var line strings.Builder
+	if entry.Time != "" {
+		line.WriteString(entry.Time)
+		line.WriteByte(' ')
+	}
+	if entry.SyslogIdentifier != "" {
+		line.WriteString(entry.SyslogIdentifier)
+		if entry.PID > 0 {
+			fmt.Fprintf(&line, "[%d]", entry.PID)
+		}
+		line.WriteString(": ")
+	}
+	line.WriteString(entry.Message)
+	line.WriteByte('\n')
+
+	_, err := io.WriteString(e.w, line.String())
+	return err
+}
+
+type ndjsonLogEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e ndjsonLogEncoder) Encode(entry types.LogEntry) error {
+	return e.encoder.Encode(entry)
+}
+
+// csvLogEncoder writes one row per entry; the header row is written by WriteLogExport
+type csvLogEncoder struct {
+	w *csv.Writer
+}
+
+func (e csvLogEncoder) Encode(entry types.LogEntry) error {
+	var priority, pid string
+	if entry.Priority != nil {
+		priority = strconv.Itoa(*entry.Priority)
+	}
+	if entry.PID > 0 {
+		pid = strconv.Itoa(entry.PID)
+	}
+	if err := e.w.Write([]string{entry.Time, priority, pid, entry.SyslogIdentifier, entry.Unit, entry.Message}); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
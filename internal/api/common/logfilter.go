@@ -0,0 +1,109 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxLogPageSize is the maximum number of entries returned by a log history request
+const MaxLogPageSize = 1000
+
+// logPriorities maps syslog priority names to their levels
+var logPriorities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+// bootPattern matches boot offsets such as "0" or "-1" and 128-bit boot IDs
+var bootPattern = regexp.MustCompile(`^(-?[0-9]+|[0-9a-fA-F]{32})$`)
+
+// ParseLogFilter parses the 'since', 'until', 'priority', 'boot', 'grep' and 'ignoreCase'
+// query parameters shared by the log endpoints
+func ParseLogFilter(c *gin.Context) (types.LogFilter, error) {
+	var filter types.LogFilter
+	var err error
+
+	if filter.Since, err = parseLogTime(c.Query("since")); err != nil {
+		return filter, fmt.Errorf("invalid since: %w", err)
+	}
+	if filter.Until, err = parseLogTime(c.Query("until")); err != nil {
+		return filter, fmt.Errorf("invalid until: %w", err)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return filter, fmt.Errorf("until must not be before since")
+	}
+
+	if priority := c.Query("priority"); priority != "" {
+		level, ok := logPriorities[strings.ToLower(priority)]
+		if n, err := strconv.Atoi(priority); err == nil && n >= 0 && n <= 7 {
+			level, ok = n, true
+		}
+		if !ok {
+			return filter, fmt.Errorf("invalid priority %q", priority)
+		}
+		filter.Priority = strconv.Itoa(level)
+	}
+
+	if boot := c.Query("boot"); boot != "" {
+		if !bootPattern.MatchString(boot) {
+			return filter, fmt.Errorf("invalid boot %q", boot)
+		}
+		filter.Boot = boot
+	}
+
+	if grep := c.Query("grep"); grep != "" {
+		if c.Query("ignoreCase") == "true" {
+			grep = "(?i)" + grep
+		}
+		if filter.Grep, err = regexp.Compile(grep); err != nil {
+			return filter, fmt.Errorf("invalid grep pattern: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseLogTime parses an RFC3339 time or a duration such as "1h30m" meaning that long ago
+func parseLogTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return time.Now().Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC3339 time nor a duration", value)
+}
+
+// ParseLogPageParams parses the 'before', 'after' and 'limit' query parameters of the
+// log history endpoints. Only one of the cursors may be given.
+func ParseLogPageParams(c *gin.Context) (before string, after string, limit int, err error) {
+	before, after = c.Query("before"), c.Query("after")
+	if before != "" && after != "" {
+		return "", "", 0, fmt.Errorf("only one of before and after may be given")
+	}
+
+	limit = DefaultLogLines
+	if limitParam := c.Query("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > MaxLogPageSize {
+			return "", "", 0, fmt.Errorf("limit must be between 1 and %d", MaxLogPageSize)
+		}
+	}
+
+	return before, after, limit, nil
+}
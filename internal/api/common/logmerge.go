@@ -0,0 +1,195 @@
+package common
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// MaxMergedLogSources is the maximum number of units and containers in a merged log stream
+const MaxMergedLogSources = 10
+
+// logMergeDelay is how long entries are held back so that entries of other sources arriving
+// slightly later can still be put in order before them
+const logMergeDelay = 500 * time.Millisecond
+
+// LogSource is a log stream of a unit or container to be merged with others
+type LogSource struct {
+	// Type of the source, "unit" or "container"
+	Type string
+	// Name of the unit or ID of the container
+	Name    string
+	Entries <-chan types.LogEntry
+	Errors  <-chan error
+}
+
+// LogLineEntries converts a stream of log lines into log entries using parse
+func LogLineEntries(ctx context.Context, lineCh <-chan string, parse func(line string) types.LogEntry) <-chan types.LogEntry {
+	entryCh := make(chan types.LogEntry)
+	go func() {
+		defer close(entryCh)
+		for line := range lineCh {
+			select {
+			case entryCh <- parse(line):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return entryCh
+}
+
+// MergeLogStreams merges the entries of several sources into one stream ordered by time and
+// tagged with their source. Errors of a source are tagged the same way; the merged stream
+// ends once every source has ended.
+func MergeLogStreams(ctx context.Context, sources []LogSource) (<-chan types.MergedLogEntry, <-chan error) {
+	outCh := make(chan types.MergedLogEntry)
+	errCh := make(chan error)
+	inCh := make(chan *pendingLogEntry)
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			forwardLogSource(ctx, source, inCh, errCh)
+		}()
+	}
+	sourcesDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(sourcesDone)
+	}()
+
+	go func() {
+		defer close(outCh)
+		// Only close errCh once no source can send to it anymore
+		defer func() {
+			<-sourcesDone
+			close(errCh)
+		}()
+
+		ticker := time.NewTicker(logMergeDelay / 5)
+		defer ticker.Stop()
+
+		var pending pendingLogEntries
+		seq := 0
+		// flush sends the entries held back for logMergeDelay, or all of them
+		flush := func(all bool) bool {
+			cutoff := time.Now().Add(-logMergeDelay)
+			for pending.Len() > 0 && (all || !pending[0].arrived.After(cutoff)) {
+				entry := heap.Pop(&pending).(*pendingLogEntry)
+				select {
+				case outCh <- entry.entry:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		for {
+			select {
+			case entry := <-inCh:
+				entry.seq = seq
+				seq++
+				heap.Push(&pending, entry)
+			case <-ticker.C:
+				if !flush(false) {
+					return
+				}
+			case <-sourcesDone:
+				flush(true)
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return outCh, errCh
+}
+
+// forwardLogSource sends the entries and errors of a source to the merger until the source ends
+func forwardLogSource(ctx context.Context, source LogSource, inCh chan<- *pendingLogEntry, errCh chan<- error) {
+	entries, errs := source.Entries, source.Errors
+	for entries != nil || errs != nil {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				entries = nil
+				continue
+			}
+			arrived := time.Now()
+			// Entries without a timestamp are ordered by their arrival
+			t, err := time.Parse(time.RFC3339Nano, entry.Time)
+			if err != nil {
+				t = arrived
+			}
+			pending := &pendingLogEntry{
+				entry: types.MergedLogEntry{
+					SourceType: source.Type,
+					Source:     source.Name,
+					LogEntry:   entry,
+				},
+				time:    t,
+				arrived: arrived,
+			}
+			select {
+			case inCh <- pending:
+			case <-ctx.Done():
+				return
+			}
+
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			select {
+			case errCh <- fmt.Errorf("%s %s: %w", source.Type, source.Name, err):
+			case <-ctx.Done():
+				return
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pendingLogEntry is an entry held back by MergeLogStreams
+type pendingLogEntry struct {
+	entry   types.MergedLogEntry
+	time    time.Time
+	arrived time.Time
+	// seq keeps entries with the same time in the order they arrived
+	seq int
+}
+
+// pendingLogEntries is a min-heap of held back entries ordered by time
+type pendingLogEntries []*pendingLogEntry
+
+func (p pendingLogEntries) Len() int { return len(p) }
+
+func (p pendingLogEntries) Less(i, j int) bool {
+	if p[i].time.Equal(p[j].time) {
+		return p[i].seq < p[j].seq
+	}
+	return p[i].time.Before(p[j].time)
+}
+
+func (p pendingLogEntries) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+func (p *pendingLogEntries) Push(x any) { *p = append(*p, x.(*pendingLogEntry)) }
+
+func (p *pendingLogEntries) Pop() any {
+	old := *p
+	entry := old[len(old)-1]
+	*p = old[:len(old)-1]
+	return entry
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// supportedSignals maps canonical signal names to their numbers
+var supportedSignals = map[string]syscall.Signal{
+	"SIGHUP":   syscall.SIGHUP,
+	"SIGINT":   syscall.SIGINT,
+	"SIGQUIT":  syscall.SIGQUIT,
+	"SIGABRT":  syscall.SIGABRT,
+	"SIGKILL":  syscall.SIGKILL,
+	"SIGUSR1":  syscall.SIGUSR1,
+	"SIGUSR2":  syscall.SIGUSR2,
+	"SIGALRM":  syscall.SIGALRM,
+	"SIGTERM":  syscall.SIGTERM,
+	"SIGCONT":  syscall.SIGCONT,
+	"SIGSTOP":  syscall.SIGSTOP,
+	"SIGTSTP":  syscall.SIGTSTP,
+	"SIGWINCH": syscall.SIGWINCH,
+}
+
+// ParseSignal normalizes a signal name such as "hup" or "SIGHUP" and returns
+// its canonical name and number
+func ParseSignal(name string) (string, syscall.Signal, error) {
+	canonical := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(canonical, "SIG") {
+		canonical = "SIG" + canonical
+	}
+
+	signal, ok := supportedSignals[canonical]
+	if !ok {
+		return "", 0, fmt.Errorf("unknown signal %q", name)
+	}
+	return canonical, signal, nil
+}
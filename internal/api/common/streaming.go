@@ -43,7 +43,7 @@ func HandleError(c *gin.Context, err error, id string, operation string, logger
 		return false
 	}
 
-	if notFoundMsg != "" && (strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "No such")) {
+	if notFoundMsg != "" && (strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "No such") || strings.Contains(err.Error(), "does not exist")) {
 		c.JSON(http.StatusNotFound, types.ErrorResponse{
 			Error: fmt.Sprintf(notFoundMsg, id),
 		})
@@ -67,6 +67,19 @@ func HandleStreamingOutput(
 	errCh <-chan error,
 	id string,
 	logger *slog.Logger,
+) {
+	HandleStreamingEvents(ctx, c, "output", outputCh, errCh, id, logger)
+}
+
+// HandleStreamingEvents handles streaming values from a channel to SSE events of the given type
+func HandleStreamingEvents[T any](
+	ctx context.Context,
+	c *gin.Context,
+	eventType string,
+	eventCh <-chan T,
+	errCh <-chan error,
+	id string,
+	logger *slog.Logger,
 ) {
 	// Create a heartbeat ticker to ensure the connection stays alive
 	heartbeatTicker := time.NewTicker(5 * time.Second)
@@ -74,14 +87,14 @@ func HandleStreamingOutput(
 
 	for {
 		select {
-		case output, ok := <-outputCh:
+		case content, ok := <-eventCh:
 			if !ok {
 				logger.Info("output channel closed", "id", id)
 				return
 			}
 			event := types.SSEvent{
-				Type:    "output",
-				Content: output,
+				Type:    eventType,
+				Content: content,
 			}
 			c.SSEvent(event.Type, event.Content)
 			c.Writer.Flush()
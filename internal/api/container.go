@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -34,9 +35,12 @@ func (h *ContainerHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("", h.listContainers)
 	rg.GET("/:id", h.getContainer)
 	rg.GET("/:id/logs", h.streamContainerLogs)
+	rg.GET("/:id/logs/history", h.getContainerLogHistory)
+	rg.GET("/:id/logs/export", h.exportContainerLogs)
 	rg.POST("/:id/start", h.startContainer)
 	rg.POST("/:id/stop", h.stopContainer)
 	rg.POST("/:id/restart", h.restartContainer)
+	rg.POST("/:id/kill", h.killContainer)
 	rg.POST("/:id/exec", h.execInContainer)
 }
 
@@ -59,17 +63,30 @@ func (h *ContainerHandler) listContainers(c *gin.Context) {
 }
 
 // @Summary     Stream container logs
-// @Description Stream logs from a container (always includes real-time updates)
+// @Description Stream logs from a container. New lines are streamed in real time unless until is given.
 // @Tags        containers, sse
 // @Produce     text/event-stream
 // @Param       id     path     string  true  "Container ID"
 // @Param       lines  query    integer false "Number of historical log lines to return before streaming new ones" default(100)
+// @Param       since  query    string  false "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param       until  query    string  false "Only lines at or before this RFC3339 time or duration ago; disables following"
+// @Param       grep   query    string  false "Regular expression the message has to match"
+// @Param       ignoreCase query boolean false "Match grep case-insensitively"
 // @Success     200    {object} types.SSEvent
+// @Failure     400    {object} types.ErrorResponse
 // @Failure     404    {object} types.SSEvent
 // @Failure     500    {object} types.SSEvent
 // @Router      /container/{id}/logs [get]
 func (h *ContainerHandler) streamContainerLogs(c *gin.Context) {
 	id := c.Param("id")
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
 	common.SetupSSE(c)
 
 	numLines := common.ParseLogQueryParams(c, h.logger)
@@ -77,8 +94,8 @@ func (h *ContainerHandler) streamContainerLogs(c *gin.Context) {
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	// Always use follow=true as we always want real-time updates
-	logCh, errCh := h.service.StreamContainerLogs(ctx, id, true, numLines)
+	// Follow for real-time updates unless the filter ends in the past
+	logCh, errCh := h.service.StreamContainerLogs(ctx, id, true, numLines, filter)
 
 	h.logger.Info("started streaming logs",
 		"container", id,
@@ -87,6 +104,104 @@ func (h *ContainerHandler) streamContainerLogs(c *gin.Context) {
 	common.HandleStreamingOutput(ctx, c, logCh, errCh, id, h.logger)
 }
 
+// @Summary     Get container log history
+// @Description Get a page of log lines of a container. Without a cursor the latest lines are returned; pass the before or after cursor of a page to get the lines preceding or following it.
+// @Tags        containers
+// @Produce     json
+// @Param       id     path     string  true  "Container ID"
+// @Param       limit  query    integer false "Maximum number of lines to return" minimum(1) maximum(1000) default(100)
+// @Param       before query    string  false "Cursor of a page to return the lines preceding it"
+// @Param       after  query    string  false "Cursor of a page to return the lines following it"
+// @Param       since  query    string  false "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param       until  query    string  false "Only lines at or before this RFC3339 time or duration ago"
+// @Param       grep   query    string  false "Regular expression the message has to match"
+// @Param       ignoreCase query boolean false "Match grep case-insensitively"
+// @Success     200    {object} types.LogPage
+// @Failure     400    {object} types.ErrorResponse
+// @Failure     404    {object} types.ErrorResponse
+// @Failure     500    {object} types.ErrorResponse
+// @Router      /container/{id}/logs/history [get]
+func (h *ContainerHandler) getContainerLogHistory(c *gin.Context) {
+	id := c.Param("id")
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	before, after, limit, err := common.ParseLogPageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	page, err := h.service.GetContainerLogHistory(c.Request.Context(), id, before, after, limit, filter)
+	if errors.Is(err, container.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if common.HandleError(c, err, id, "get log history of container", h.logger, "Container %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully got container log history",
+		"container", id,
+		"lines", len(page.Entries))
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary     Export container logs
+// @Description Download all log lines of a container matching the filters. Exports are streamed; large ones are gzip-compressed unless compression is none.
+// @Tags        containers
+// @Produce     plain,application/x-ndjson,text/csv,application/gzip
+// @Param       id     path     string  true  "Container ID"
+// @Param       format query    string  false "File format" Enums(text, ndjson, csv) default(text)
+// @Param       compression query string false "Compression of the download; auto compresses exports larger than 1 MiB" Enums(auto, gzip, none) default(auto)
+// @Param       since  query    string  false "Only lines at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param       until  query    string  false "Only lines at or before this RFC3339 time or duration ago"
+// @Param       grep   query    string  false "Regular expression the message has to match"
+// @Param       ignoreCase query boolean false "Match grep case-insensitively"
+// @Success     200    {file}   file
+// @Failure     400    {object} types.ErrorResponse
+// @Failure     404    {object} types.ErrorResponse
+// @Failure     500    {object} types.ErrorResponse
+// @Router      /container/{id}/logs/export [get]
+func (h *ContainerHandler) exportContainerLogs(c *gin.Context) {
+	id := c.Param("id")
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	format, compression, err := common.ParseLogExportParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if format == common.LogExportJournal {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "the export format is only available for systemd units",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	logCh, errCh := h.service.ExportContainerLogs(ctx, id, filter)
+	err = common.WriteLogExport(c, id, format, compression, logCh, errCh, h.logger)
+	common.HandleError(c, err, id, "export logs of container", h.logger, "Container %s not found")
+}
+
 // @Summary     Execute command in container
 // @Description Execute a command in a container and stream the output
 // @Tags        containers, sse
@@ -219,3 +334,46 @@ func (h *ContainerHandler) restartContainer(c *gin.Context) {
 		Message: fmt.Sprintf("Container %s restarted successfully", id),
 	})
 }
+
+// @Summary     Kill container
+// @Description Send a signal to the main process of a container
+// @Tags        containers
+// @Accept      json
+// @Produce     json
+// @Param       id     path     string                     true "Container ID"
+// @Param       signal body     types.ContainerKillRequest true "Signal to send"
+// @Success     200    {object} types.Message
+// @Failure     400    {object} types.ErrorResponse
+// @Failure     404    {object} types.ErrorResponse
+// @Failure     500    {object} types.ErrorResponse
+// @Router      /container/{id}/kill [post]
+func (h *ContainerHandler) killContainer(c *gin.Context) {
+	id := c.Param("id")
+
+	var killReq types.ContainerKillRequest
+	if err := c.ShouldBindJSON(&killReq); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	signalName, _, err := common.ParseSignal(killReq.Signal)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid signal: %s", killReq.Signal),
+		})
+		return
+	}
+
+	h.logger.Info("sending signal to container", "id", id, "signal", signalName)
+
+	err = h.service.KillContainer(c.Request.Context(), id, signalName)
+	if common.HandleError(c, err, id, "kill container", h.logger, "Container %s not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Signal %s sent to container %s successfully", signalName, id),
+	})
+}
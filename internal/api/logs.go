@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Keyruu/sirberus/internal/api/common"
+	"github.com/Keyruu/sirberus/internal/container"
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// LogsHandler serves logs spanning systemd units and containers
+type LogsHandler struct {
+	systemd    *SystemdHandler
+	containers *ContainerHandler
+	logger     *slog.Logger
+}
+
+// NewLogsHandler creates a handler using the services of the given handlers.
+// Either handler may be nil if its backend is disabled.
+func NewLogsHandler(logger *slog.Logger, systemdHandler *SystemdHandler, containerHandler *ContainerHandler) *LogsHandler {
+	return &LogsHandler{
+		systemd:    systemdHandler,
+		containers: containerHandler,
+		logger:     logger.With("component", "logs_handler"),
+	}
+}
+
+func (h *LogsHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", h.streamMergedLogs)
+}
+
+// @Summary		Stream merged logs
+// @Description	Stream the logs of several systemd units and containers merged into one stream ordered by time. Every entry is tagged with its source. Entries are held back briefly to put them in order.
+// @Tags			logs, sse
+// @Produce		text/event-stream
+// @Param			unit		query		[]string	false	"Unit names (e.g. nginx or backup.timer)"	collectionFormat(multi)
+// @Param			container	query		[]string	false	"Container IDs"	collectionFormat(multi)
+// @Param			lines		query		integer	false	"Number of historical log lines per source to return before streaming new ones"	default(100)
+// @Param			since		query		string	false	"Only entries at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param			until		query		string	false	"Only entries at or before this RFC3339 time or duration ago; disables following"
+// @Param			priority	query		string	false	"Maximum priority of unit entries (0-7 or emerg, alert, crit, err, warning, notice, info, debug)"
+// @Param			boot		query		string	false	"Boot offset (0 current, -1 previous) or boot ID of unit entries"
+// @Param			grep		query		string	false	"Regular expression the message has to match"
+// @Param			ignoreCase	query		boolean	false	"Match grep case-insensitively"
+// @Param			scope	query		string	false	"Systemd manager scope of the units"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SSEvent{content=types.MergedLogEntry}
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.SSEvent
+// @Router			/logs [get]
+func (h *LogsHandler) streamMergedLogs(c *gin.Context) {
+	units, containers := c.QueryArray("unit"), c.QueryArray("container")
+	if count := len(units) + len(containers); count == 0 || count > common.MaxMergedLogSources {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Between 1 and %d units and containers are required", common.MaxMergedLogSources),
+		})
+		return
+	}
+	if len(units) > 0 && h.systemd == nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Systemd support is disabled",
+		})
+		return
+	}
+	if len(containers) > 0 && h.containers == nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Container support is disabled",
+		})
+		return
+	}
+
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	numLines := common.ParseLogQueryParams(c, h.logger)
+	sources := make([]common.LogSource, 0, len(units)+len(containers))
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if len(units) > 0 {
+		service, ok := h.systemd.scopedService(c)
+		if !ok {
+			return
+		}
+		for _, unit := range units {
+			name := getUnitName(unit)
+			logCh, errCh := service.StreamServiceLogs(ctx, name, true, numLines, filter)
+			sources = append(sources, common.LogSource{
+				Type:    "unit",
+				Name:    name,
+				Entries: logCh,
+				Errors:  errCh,
+			})
+		}
+	}
+	for _, id := range containers {
+		lineCh, errCh := h.containers.service.StreamContainerLogs(ctx, id, true, numLines, filter)
+		sources = append(sources, common.LogSource{
+			Type:    "container",
+			Name:    id,
+			Entries: common.LogLineEntries(ctx, lineCh, container.ParseLogLine),
+			Errors:  errCh,
+		})
+	}
+
+	common.SetupSSE(c)
+
+	mergedCh, errCh := common.MergeLogStreams(ctx, sources)
+
+	h.logger.Info("started streaming merged logs",
+		"units", units,
+		"containers", containers)
+
+	common.HandleStreamingEvents(ctx, c, "log", mergedCh, errCh, "logs", h.logger)
+}
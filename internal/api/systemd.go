@@ -2,9 +2,12 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/Keyruu/sirberus/internal/api/common"
@@ -34,104 +37,635 @@ func NewSystemdHandler(logger *slog.Logger) (*SystemdHandler, error) {
 
 func (h *SystemdHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	rg.GET("", h.listServices)
+	rg.POST("", h.createService)
+	rg.GET("/timers", h.listTimers)
+	rg.GET("/events", h.streamUnitEvents)
+	rg.GET("/jobs", h.listJobs)
+	rg.GET("/boots", h.listBoots)
+	rg.GET("/analyze", h.analyzeBoot)
+	rg.GET("/security", h.getSecurityReport)
+	rg.GET("/failed", h.listFailedUnits)
+	rg.GET("/journal", h.streamSystemLogs)
+	rg.GET("/jobs/:id", h.streamJob)
+	rg.DELETE("/jobs/:id", h.cancelJob)
+	rg.POST("/run", h.runTransientUnit)
 	rg.GET("/:name", h.getService)
+	rg.DELETE("/:name", h.deleteService)
 	rg.GET("/:name/logs", h.streamServiceLogs)
+	rg.GET("/:name/logs/history", h.getServiceLogHistory)
+	rg.GET("/:name/logs/export", h.exportServiceLogs)
+	rg.GET("/:name/unit-file", h.getUnitFile)
+	rg.GET("/:name/dependencies", h.getServiceDependencies)
+	rg.GET("/:name/security", h.getServiceSecurity)
+	rg.PUT("/:name/overrides/:override", h.writeOverride)
+	rg.DELETE("/:name/overrides/:override", h.deleteOverride)
 	rg.POST("/:name/start", h.startService)
 	rg.POST("/:name/stop", h.stopService)
 	rg.POST("/:name/restart", h.restartService)
+	rg.POST("/:name/reload", h.reloadService)
+	rg.POST("/:name/try-restart", h.tryRestartService)
+	rg.POST("/:name/reload-or-restart", h.reloadOrRestartService)
+	rg.POST("/:name/reset-failed", h.resetFailedService)
+	rg.POST("/:name/kill", h.killService)
+	rg.POST("/:name/trigger", h.triggerTimer)
+	rg.POST("/:name/properties", h.setServiceProperties)
+	rg.POST("/:name/enable", h.enableService)
+	rg.POST("/:name/disable", h.disableService)
+	rg.POST("/:name/mask", h.maskService)
+	rg.POST("/:name/unmask", h.unmaskService)
 }
 
 // @Summary		Start service
-// @Description	Start a systemd service
+// @Description	Start a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
 // @Tags			systemd
 // @Produce		json
-// @Param			name	path		string	true	"Service name"
-// @Success		200		{object}	types.Message
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
 // @Failure		404		{object}	types.ErrorResponse
 // @Failure		500		{object}	types.ErrorResponse
 // @Router			/systemd/{name}/start [post]
 func (h *SystemdHandler) startService(c *gin.Context) {
-	name := getServiceName(c.Param("name"))
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
 
-	err := h.service.StartUnit(name)
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.StartUnit(name, mode)
 	if common.HandleError(c, err, name, "start service", h.logger, "Service %s not found") {
 		return
 	}
 
-	h.logger.Info("successfully started service",
-		"service", name)
-	c.JSON(http.StatusOK, types.Message{
-		Message: fmt.Sprintf("Service %s started successfully", name),
-	})
+	h.logger.Info("queued start job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
 }
 
 // @Summary		Stop service
-// @Description	Stop a systemd service
+// @Description	Stop a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
 // @Tags			systemd
 // @Produce		json
-// @Param			name	path		string	true	"Service name"
-// @Success		200		{object}	types.Message
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
 // @Failure		404		{object}	types.ErrorResponse
 // @Failure		500		{object}	types.ErrorResponse
 // @Router			/systemd/{name}/stop [post]
 func (h *SystemdHandler) stopService(c *gin.Context) {
-	name := getServiceName(c.Param("name"))
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
 
-	err := h.service.StopUnit(name)
+	job, err := service.StopUnit(name, mode)
 	if common.HandleError(c, err, name, "stop service", h.logger, "Service %s not found") {
 		return
 	}
 
-	h.logger.Info("successfully stopped service",
-		"service", name)
-	c.JSON(http.StatusOK, types.Message{
-		Message: fmt.Sprintf("Service %s stopped successfully", name),
-	})
+	h.logger.Info("queued stop job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
 }
 
 // @Summary		Restart service
-// @Description	Restart a systemd service
+// @Description	Restart a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
 // @Tags			systemd
 // @Produce		json
-// @Param			name	path		string	true	"Service name"
-// @Success		200		{object}	types.Message
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
 // @Failure		404		{object}	types.ErrorResponse
 // @Failure		500		{object}	types.ErrorResponse
 // @Router			/systemd/{name}/restart [post]
 func (h *SystemdHandler) restartService(c *gin.Context) {
-	name := getServiceName(c.Param("name"))
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
 
-	err := h.service.RestartUnit(name)
+	job, err := service.RestartUnit(name, mode)
 	if common.HandleError(c, err, name, "restart service", h.logger, "Service %s not found") {
 		return
 	}
 
-	h.logger.Info("successfully restarted service",
+	h.logger.Info("queued restart job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Reload service
+// @Description	Reload the configuration of a systemd service without restarting it. Returns the queued job, which can be followed at /systemd/jobs/{id}
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/reload [post]
+func (h *SystemdHandler) reloadService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.ReloadUnit(name, mode)
+	if common.HandleError(c, err, name, "reload service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("queued reload job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Try-restart service
+// @Description	Restart a systemd service only if it is currently running. Returns the queued job, which can be followed at /systemd/jobs/{id}
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/try-restart [post]
+func (h *SystemdHandler) tryRestartService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.TryRestartUnit(name, mode)
+	if common.HandleError(c, err, name, "try-restart service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("queued try-restart job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Reload or restart service
+// @Description	Reload a systemd service if it supports reloading, otherwise restart it. Returns the queued job, which can be followed at /systemd/jobs/{id}
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			mode	query		string	false	"Job mode"	Enums(replace, fail, isolate, ignore-dependencies)	default(replace)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/reload-or-restart [post]
+func (h *SystemdHandler) reloadOrRestartService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	mode, ok := parseJobMode(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.ReloadOrRestartUnit(name, mode)
+	if common.HandleError(c, err, name, "reload-or-restart service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("queued reload-or-restart job",
+		"service", name,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Reset failed service
+// @Description	Reset the failed state and restart counter of a systemd service
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.Message
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/reset-failed [post]
+func (h *SystemdHandler) resetFailedService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	err := service.ResetFailedUnit(name)
+	if common.HandleError(c, err, name, "reset failed service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully reset failed service",
 		"service", name)
 	c.JSON(http.StatusOK, types.Message{
-		Message: fmt.Sprintf("Service %s restarted successfully", name),
+		Message: fmt.Sprintf("Failed state of service %s reset successfully", name),
+	})
+}
+
+// @Summary		Kill service
+// @Description	Send a signal to the main, control or all processes of a systemd service
+// @Tags			systemd
+// @Accept			json
+// @Produce		json
+// @Param			name	path		string						true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			signal	body		types.SystemdKillRequest	true	"Signal to send"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.Message
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/kill [post]
+func (h *SystemdHandler) killService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	var killReq types.SystemdKillRequest
+	if err := c.ShouldBindJSON(&killReq); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	signalName, signal, err := common.ParseSignal(killReq.Signal)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid signal: %s", killReq.Signal),
+		})
+		return
+	}
+
+	if !systemd.IsValidKillTarget(killReq.Target) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid target: %s", killReq.Target),
+		})
+		return
+	}
+
+	err = service.KillUnit(name, killReq.Target, int32(signal))
+	if common.HandleError(c, err, name, "kill service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully sent signal to service",
+		"service", name,
+		"signal", signalName,
+		"target", killReq.Target)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Signal %s sent to service %s successfully", signalName, name),
+	})
+}
+
+// @Summary		Set service properties
+// @Description	Change resource properties (CPUQuota, MemoryMax, MemoryHigh, TasksMax, IOWeight, AllowedCPUs) of a running systemd service. Changes are lost on reboot unless they are marked persistent. If the persistent changes fail after the runtime ones were applied, the error says so.
+// @Tags			systemd
+// @Accept			json
+// @Produce		json
+// @Param			name		path		string								true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			properties	body		types.SystemdSetPropertiesRequest	true	"Property changes"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200			{object}	types.Message
+// @Failure		400			{object}	types.ErrorResponse
+// @Failure		404			{object}	types.ErrorResponse
+// @Failure		500			{object}	types.ErrorResponse
+// @Router			/systemd/{name}/properties [post]
+func (h *SystemdHandler) setServiceProperties(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	var propsReq types.SystemdSetPropertiesRequest
+	if err := c.ShouldBindJSON(&propsReq); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	err := service.SetUnitProperties(name, propsReq.Changes)
+	if errors.Is(err, systemd.ErrInvalidProperty) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if common.HandleError(c, err, name, "set properties of service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully set service properties",
+		"service", name,
+		"changes", propsReq.Changes)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Properties of service %s updated successfully", name),
+	})
+}
+
+// parseJobMode reads the 'mode' query parameter and writes a 400 response if it is not a valid job mode
+func parseJobMode(c *gin.Context) (string, bool) {
+	mode := c.Query("mode")
+	if !systemd.IsValidJobMode(mode) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid job mode: %s", mode),
+		})
+		return "", false
+	}
+	return mode, true
+}
+
+// scopedService returns the service for the manager selected by the 'scope' and 'uid'
+// query parameters and writes a 400 response if they are invalid
+func (h *SystemdHandler) scopedService(c *gin.Context) (*systemd.SystemdService, bool) {
+	scope, err := systemd.ParseScope(c.Query("scope"), c.Query("uid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return nil, false
+	}
+	return h.service.ForScope(scope), true
+}
+
+// requireSystemScope writes a 400 response if the 'scope' and 'uid' query parameters select a
+// user manager, for endpoints that only work with the system manager and /etc/systemd/system
+func requireSystemScope(c *gin.Context, operation string) bool {
+	scope, err := systemd.ParseScope(c.Query("scope"), c.Query("uid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return false
+	}
+	if scope.User {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("%s is only supported for the system manager", operation),
+		})
+		return false
+	}
+	return true
+}
+
+// parseJobID reads the 'id' path parameter and writes a 400 response if it is not a valid job ID
+func parseJobID(c *gin.Context) (uint32, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid job ID: %s", c.Param("id")),
+		})
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// @Summary		Enable service
+// @Description	Enable a systemd service so it starts at boot
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdUnitFileChangeResponse
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/enable [post]
+func (h *SystemdHandler) enableService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	changes, err := service.EnableUnit(name)
+	if common.HandleError(c, err, name, "enable service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully enabled service",
+		"service", name,
+		"changes", len(changes))
+	c.JSON(http.StatusOK, types.SystemdUnitFileChangeResponse{
+		Message: fmt.Sprintf("Service %s enabled successfully", name),
+		Changes: changes,
+	})
+}
+
+// @Summary		Disable service
+// @Description	Disable a systemd service so it no longer starts at boot
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdUnitFileChangeResponse
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/disable [post]
+func (h *SystemdHandler) disableService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	changes, err := service.DisableUnit(name)
+	if common.HandleError(c, err, name, "disable service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully disabled service",
+		"service", name,
+		"changes", len(changes))
+	c.JSON(http.StatusOK, types.SystemdUnitFileChangeResponse{
+		Message: fmt.Sprintf("Service %s disabled successfully", name),
+		Changes: changes,
+	})
+}
+
+// @Summary		Mask service
+// @Description	Mask a systemd service so it cannot be started
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdUnitFileChangeResponse
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/mask [post]
+func (h *SystemdHandler) maskService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	changes, err := service.MaskUnit(name)
+	if common.HandleError(c, err, name, "mask service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully masked service",
+		"service", name,
+		"changes", len(changes))
+	c.JSON(http.StatusOK, types.SystemdUnitFileChangeResponse{
+		Message: fmt.Sprintf("Service %s masked successfully", name),
+		Changes: changes,
+	})
+}
+
+// @Summary		Unmask service
+// @Description	Unmask a systemd service so it can be started again
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdUnitFileChangeResponse
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/unmask [post]
+func (h *SystemdHandler) unmaskService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	changes, err := service.UnmaskUnit(name)
+	if common.HandleError(c, err, name, "unmask service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully unmasked service",
+		"service", name,
+		"changes", len(changes))
+	c.JSON(http.StatusOK, types.SystemdUnitFileChangeResponse{
+		Message: fmt.Sprintf("Service %s unmasked successfully", name),
+		Changes: changes,
 	})
 }
 
-func getServiceName(name string) string {
-	if !strings.HasSuffix(name, ".service") {
+// getUnitName returns the fully qualified unit name, treating names without
+// a unit type suffix as services
+func getUnitName(name string) string {
+	if systemd.UnitType(name) == "" {
 		return name + ".service"
 	}
 	return name
 }
 
 // @Summary		Stream service logs
-// @Description	Stream logs from a systemd service (always includes real-time updates)
+// @Description	Stream logs from a systemd service. New entries are streamed in real time unless until or an earlier boot is selected.
 // @Tags			systemd, sse
 // @Produce		text/event-stream
-// @Param			name	path		string	true	"Service name"
-// @Param			lines	query		integer	false	"Number of historical log lines to return before streaming new ones"	default(100)
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			lines		query		integer	false	"Number of historical log lines to return before streaming new ones"	default(100)
+// @Param			since		query		string	false	"Only entries at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param			until		query		string	false	"Only entries at or before this RFC3339 time or duration ago; disables following"
+// @Param			priority	query		string	false	"Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)"
+// @Param			boot		query		string	false	"Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots"
+// @Param			grep		query		string	false	"Regular expression the message has to match"
+// @Param			ignoreCase	query		boolean	false	"Match grep case-insensitively"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
 // @Success		200		{object}	types.SSEvent
+// @Failure		400		{object}	types.ErrorResponse
 // @Failure		404		{object}	types.SSEvent
 // @Failure		500		{object}	types.SSEvent
 // @Router			/systemd/{name}/logs [get]
 func (h *SystemdHandler) streamServiceLogs(c *gin.Context) {
-	name := getServiceName(c.Param("name"))
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
 	common.SetupSSE(c)
 
 	numLines := common.ParseLogQueryParams(c, h.logger)
@@ -139,8 +673,8 @@ func (h *SystemdHandler) streamServiceLogs(c *gin.Context) {
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	// Always use follow=true as we always want real-time updates
-	logCh, errCh := h.service.StreamServiceLogs(ctx, name, true, numLines)
+	// Follow for real-time updates unless the filter ends in the past
+	logCh, errCh := service.StreamServiceLogs(ctx, name, true, numLines, filter)
 
 	h.logger.Info("started streaming logs",
 		"service", name,
@@ -149,50 +683,909 @@ func (h *SystemdHandler) streamServiceLogs(c *gin.Context) {
 	h.handleLogStreaming(ctx, c, logCh, errCh, name)
 }
 
-func (h *SystemdHandler) handleLogStreaming(
-	ctx context.Context,
-	c *gin.Context,
-	logCh <-chan string,
-	errCh <-chan error,
-	serviceName string,
-) {
-	common.HandleStreamingOutput(ctx, c, logCh, errCh, serviceName, h.logger)
-}
-
-// @Summary		Get systemd service details
-// @Description	Get detailed information about a specific systemd service
+// @Summary		Get service log history
+// @Description	Get a page of journal entries of a systemd unit. Without a cursor the latest entries are returned; pass the before or after cursor of a page to get the entries preceding or following it.
 // @Tags			systemd
 // @Produce		json
-// @Param			name	path		string	true	"Service name"
-// @Success		200		{object}	types.SystemdServiceDetails
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			limit		query		integer	false	"Maximum number of entries to return"	minimum(1)	maximum(1000)	default(100)
+// @Param			before		query		string	false	"Cursor of a page to return the entries preceding it"
+// @Param			after		query		string	false	"Cursor of a page to return the entries following it"
+// @Param			since		query		string	false	"Only entries at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param			until		query		string	false	"Only entries at or before this RFC3339 time or duration ago"
+// @Param			priority	query		string	false	"Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)"
+// @Param			boot		query		string	false	"Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots"
+// @Param			grep		query		string	false	"Regular expression the message has to match"
+// @Param			ignoreCase	query		boolean	false	"Match grep case-insensitively"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.LogPage
+// @Failure		400		{object}	types.ErrorResponse
 // @Failure		404		{object}	types.ErrorResponse
 // @Failure		500		{object}	types.ErrorResponse
-// @Router			/systemd/{name} [get]
-func (h *SystemdHandler) getService(c *gin.Context) {
-	name := getServiceName(c.Param("name"))
-	details, err := h.service.GetUnitDetails(name)
-	if common.HandleError(c, err, name, "get details for service", h.logger, "Service %s not found") {
+// @Router			/systemd/{name}/logs/history [get]
+func (h *SystemdHandler) getServiceLogHistory(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
 		return
 	}
 
-	h.logger.Info("successfully got service details",
-		"service", name)
-	c.JSON(http.StatusOK, details)
-}
+	name := getUnitName(c.Param("name"))
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	before, after, limit, err := common.ParseLogPageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
 
-// @Summary		List systemd services
-// @Description	Get a list of all systemd services
-// @Tags			systemd
-// @Produce		json
-// @Success		200	{object}	types.SystemdServiceList
-// @Failure		500	{object}	types.ErrorResponse
-// @Router			/systemd [get]
-func (h *SystemdHandler) listServices(c *gin.Context) {
-	services, err := h.service.ListUnits()
-	if common.HandleError(c, err, "", "list services", h.logger, "") {
+	page, err := service.GetServiceLogHistory(c.Request.Context(), name, before, after, limit, filter)
+	if errors.Is(err, systemd.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if common.HandleError(c, err, name, "get log history of service", h.logger, "Service %s not found") {
 		return
 	}
 
-	h.logger.Info("successfully listed services", "count", len(services.Services))
-	c.JSON(http.StatusOK, services)
+	h.logger.Info("successfully got service log history",
+		"service", name,
+		"entries", len(page.Entries))
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary		Export service logs
+// @Description	Download all journal entries of a systemd unit matching the filters. Exports are streamed; large ones are gzip-compressed unless compression is none.
+// @Tags			systemd
+// @Produce		plain,application/x-ndjson,text/csv,application/vnd.fdo.journal,application/gzip
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			format		query		string	false	"File format; export is the journal export format with all fields and does not support grep"	Enums(text, ndjson, csv, export)	default(text)
+// @Param			compression	query		string	false	"Compression of the download; auto compresses exports larger than 1 MiB"	Enums(auto, gzip, none)	default(auto)
+// @Param			since		query		string	false	"Only entries at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param			until		query		string	false	"Only entries at or before this RFC3339 time or duration ago"
+// @Param			priority	query		string	false	"Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)"
+// @Param			boot		query		string	false	"Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots"
+// @Param			grep		query		string	false	"Regular expression the message has to match"
+// @Param			ignoreCase	query		boolean	false	"Match grep case-insensitively"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{file}		file
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/logs/export [get]
+func (h *SystemdHandler) exportServiceLogs(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	format, compression, err := common.ParseLogExportParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if format == common.LogExportJournal && filter.Grep != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "grep is not supported with the export format",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	if format == common.LogExportJournal {
+		err = common.WriteRawLogExport(c, name, format, compression, func(w io.Writer) error {
+			return service.ExportServiceJournal(ctx, name, filter, w)
+		}, h.logger)
+	} else {
+		logCh, errCh := service.ExportServiceLogs(ctx, name, filter)
+		err = common.WriteLogExport(c, name, format, compression, logCh, errCh, h.logger)
+	}
+	common.HandleError(c, err, name, "export logs of service", h.logger, "Service %s not found")
+}
+
+// @Summary		Stream system journal
+// @Description	Stream the journal of the whole system, or only kernel messages like journalctl -k. New entries are streamed in real time unless until or an earlier boot is selected.
+// @Tags			systemd, sse
+// @Produce		text/event-stream
+// @Param			kernel		query		boolean		false	"Only kernel messages; defaults to the current boot"
+// @Param			identifier	query		[]string	false	"Syslog identifiers of the messages (e.g. kernel or sshd)"	collectionFormat(multi)
+// @Param			lines		query		integer	false	"Number of historical log lines to return before streaming new ones"	default(100)
+// @Param			since		query		string	false	"Only entries at or after this RFC3339 time or duration ago (e.g. 1h)"
+// @Param			until		query		string	false	"Only entries at or before this RFC3339 time or duration ago; disables following"
+// @Param			priority	query		string	false	"Maximum priority (0-7 or emerg, alert, crit, err, warning, notice, info, debug)"
+// @Param			boot		query		string	false	"Boot offset (0 current, -1 previous) or boot ID as listed by /systemd/boots"
+// @Param			grep		query		string	false	"Regular expression the message has to match"
+// @Param			ignoreCase	query		boolean	false	"Match grep case-insensitively"
+// @Success		200		{object}	types.SSEvent{content=types.LogEntry}
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.SSEvent
+// @Router			/systemd/journal [get]
+func (h *SystemdHandler) streamSystemLogs(c *gin.Context) {
+	if !requireSystemScope(c, "Streaming the system journal") {
+		return
+	}
+
+	filter, err := common.ParseLogFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	kernel := c.Query("kernel") == "true"
+	identifiers := c.QueryArray("identifier")
+
+	common.SetupSSE(c)
+
+	numLines := common.ParseLogQueryParams(c, h.logger)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Follow for real-time updates unless the filter ends in the past
+	logCh, errCh := h.service.StreamSystemLogs(ctx, kernel, identifiers, true, numLines, filter)
+
+	h.logger.Info("started streaming system journal",
+		"kernel", kernel,
+		"identifiers", identifiers,
+		"lines", numLines)
+
+	h.handleLogStreaming(ctx, c, logCh, errCh, "journal")
+}
+
+// @Summary		Stream unit state changes
+// @Description	Stream an event whenever the load, active or sub state of a unit changes. Events are pushed from systemd D-Bus signals.
+// @Tags			systemd
+// @Produce		text/event-stream
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200	{object}	types.SSEvent{content=types.SystemdUnitEvent}
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.SSEvent
+// @Router			/systemd/events [get]
+func (h *SystemdHandler) streamUnitEvents(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	common.SetupSSE(c)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	eventCh, errCh := service.WatchUnitEvents(ctx)
+
+	h.logger.Info("started streaming unit events")
+
+	common.HandleStreamingEvents(ctx, c, "unit", eventCh, errCh, "events", h.logger)
+}
+
+// @Summary		List jobs
+// @Description	List the jobs queued in systemd and the recently finished jobs started through Sirberus
+// @Tags			systemd
+// @Produce		json
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200	{object}	types.SystemdJobList
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.ErrorResponse
+// @Router			/systemd/jobs [get]
+func (h *SystemdHandler) listJobs(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := service.ListJobs()
+	if common.HandleError(c, err, "", "list jobs", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully listed jobs",
+		"count", jobs.Count)
+	c.JSON(http.StatusOK, jobs)
+}
+
+// @Summary		List boots
+// @Description	List the boots recorded in the journal with the times of their first and last entry, like journalctl --list-boots. Their index or ID can be used as the boot filter of the log endpoints.
+// @Tags			systemd
+// @Produce		json
+// @Success		200	{object}	types.SystemdBootList
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.ErrorResponse
+// @Router			/systemd/boots [get]
+func (h *SystemdHandler) listBoots(c *gin.Context) {
+	if !requireSystemScope(c, "Listing boots") {
+		return
+	}
+
+	boots, err := h.service.ListBoots()
+	if common.HandleError(c, err, "", "list boots", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully listed boots",
+		"count", boots.Count)
+	c.JSON(http.StatusOK, boots)
+}
+
+// @Summary		Analyze boot performance
+// @Description	Get the activation time of every unit, slowest first, and the critical chain to a target, like systemd-analyze blame and critical-chain. Activation times are computed from the InactiveExitTimestamp, ActiveEnterTimestamp and ExecMainStartTimestamp properties.
+// @Tags			systemd
+// @Produce		json
+// @Param			target	query		string	false	"Target the critical chain leads to"	default(default.target)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdBootAnalysis
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/analyze [get]
+func (h *SystemdHandler) analyzeBoot(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	target := getUnitName(c.DefaultQuery("target", systemd.DefaultAnalyzeTarget))
+	analysis, err := service.AnalyzeBoot(target)
+	if common.HandleError(c, err, target, "analyze boot", h.logger, "Unit %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully analyzed boot",
+		"target", target,
+		"units", len(analysis.Blame))
+	c.JSON(http.StatusOK, analysis)
+}
+
+// @Summary		List failed units
+// @Description	Get all failed units with their result, the exit status of the main process, the number of restarts, when they failed and the last log entries of the failed run, most recent failure first
+// @Tags			systemd
+// @Produce		json
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdFailedUnitList
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/failed [get]
+func (h *SystemdHandler) listFailedUnits(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	failed, err := service.ListFailedUnits()
+	if common.HandleError(c, err, "", "list failed units", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully listed failed units",
+		"count", failed.Count)
+	c.JSON(http.StatusOK, failed)
+}
+
+// @Summary		Get security report
+// @Description	Get the sandboxing exposure of every loaded service, most exposed first, to prioritize which services to harden. The exposure is computed like systemd-analyze security.
+// @Tags			systemd
+// @Produce		json
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdSecurityReport
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/security [get]
+func (h *SystemdHandler) getSecurityReport(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	report, err := service.GetSecurityReport()
+	if common.HandleError(c, err, "", "get security report", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully got security report",
+		"count", report.Count)
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary		Stream job progress
+// @Description	Stream the state of a job whenever it changes. The stream ends with the finished job and its result.
+// @Tags			systemd
+// @Produce		text/event-stream
+// @Param			id	path		integer	true	"Job ID"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200	{object}	types.SSEvent{content=types.SystemdJob}
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		404	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.ErrorResponse
+// @Router			/systemd/jobs/{id} [get]
+func (h *SystemdHandler) streamJob(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	// Check the job up front so unknown jobs get a proper status code
+	_, err := service.GetJob(id)
+	if common.HandleError(c, err, c.Param("id"), "get job", h.logger, "Job %s not found") {
+		return
+	}
+
+	common.SetupSSE(c)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	jobCh, errCh := service.WatchJob(ctx, id)
+
+	h.logger.Info("started streaming job",
+		"job", id)
+
+	common.HandleStreamingEvents(ctx, c, "job", jobCh, errCh, c.Param("id"), h.logger)
+}
+
+// @Summary		Cancel job
+// @Description	Cancel a queued or running systemd job
+// @Tags			systemd
+// @Produce		json
+// @Param			id	path		integer	true	"Job ID"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200	{object}	types.Message
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		404	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.ErrorResponse
+// @Router			/systemd/jobs/{id} [delete]
+func (h *SystemdHandler) cancelJob(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	err := service.CancelJob(id)
+	if common.HandleError(c, err, c.Param("id"), "cancel job", h.logger, "Job %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully canceled job",
+		"job", id)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Job %d canceled successfully", id),
+	})
+}
+
+func (h *SystemdHandler) handleLogStreaming(
+	ctx context.Context,
+	c *gin.Context,
+	logCh <-chan types.LogEntry,
+	errCh <-chan error,
+	serviceName string,
+) {
+	common.HandleStreamingEvents(ctx, c, "output", logCh, errCh, serviceName, h.logger)
+}
+
+// @Summary		Get systemd service details
+// @Description	Get detailed information about a specific systemd service
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdServiceDetails
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name} [get]
+func (h *SystemdHandler) getService(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	details, err := service.GetUnitDetails(name)
+	if common.HandleError(c, err, name, "get details for service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully got service details",
+		"service", name)
+	c.JSON(http.StatusOK, details)
+}
+
+// @Summary		Get unit file
+// @Description	Get the unit file and its drop-in overrides in the order they are applied, like systemctl cat
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdUnitFile
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/unit-file [get]
+func (h *SystemdHandler) getUnitFile(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	unitFile, err := service.GetUnitFile(name)
+	if common.HandleError(c, err, name, "get unit file for service", h.logger, "Unit file for %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully got unit file",
+		"service", name,
+		"files", len(unitFile.Files))
+	c.JSON(http.StatusOK, unitFile)
+}
+
+// @Summary		Write drop-in override
+// @Description	Create or update a drop-in override in /etc/systemd/system/<unit>.d, verify the unit and reload systemd. The override is verified before it is installed and left unchanged if verification fails.
+// @Tags			systemd
+// @Accept			json
+// @Produce		json
+// @Param			name		path		string							true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			override	path		string							true	"Drop-in name (e.g. override or 10-limits.conf)"
+// @Param			content		body		types.SystemdOverrideRequest	true	"Drop-in content"
+// @Success		200			{object}	types.Message
+// @Failure		400			{object}	types.ErrorResponse
+// @Failure		422			{object}	types.ErrorResponse
+// @Failure		500			{object}	types.ErrorResponse
+// @Router			/systemd/{name}/overrides/{override} [put]
+func (h *SystemdHandler) writeOverride(c *gin.Context) {
+	if !requireSystemScope(c, "Writing overrides") {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	override := c.Param("override")
+
+	var overrideReq types.SystemdOverrideRequest
+	if err := c.ShouldBindJSON(&overrideReq); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	err := h.service.WriteOverride(name, override, overrideReq.Content)
+	if h.handleUnitFileError(c, err, name, "write override for service") {
+		return
+	}
+
+	h.logger.Info("successfully wrote override",
+		"service", name,
+		"override", override)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Override %s for service %s written successfully", override, name),
+	})
+}
+
+// @Summary		Delete drop-in override
+// @Description	Delete a drop-in override from /etc/systemd/system/<unit>.d, verify the unit and reload systemd. The unit is verified without the override before it is deleted, which is refused if verification fails.
+// @Tags			systemd
+// @Produce		json
+// @Param			name		path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			override	path		string	true	"Drop-in name (e.g. override or 10-limits.conf)"
+// @Success		200			{object}	types.Message
+// @Failure		400			{object}	types.ErrorResponse
+// @Failure		404			{object}	types.ErrorResponse
+// @Failure		422			{object}	types.ErrorResponse
+// @Failure		500			{object}	types.ErrorResponse
+// @Router			/systemd/{name}/overrides/{override} [delete]
+func (h *SystemdHandler) deleteOverride(c *gin.Context) {
+	if !requireSystemScope(c, "Deleting overrides") {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	override := c.Param("override")
+
+	err := h.service.DeleteOverride(name, override)
+	if h.handleUnitFileError(c, err, name, "delete override for service") {
+		return
+	}
+
+	h.logger.Info("successfully deleted override",
+		"service", name,
+		"override", override)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Override %s for service %s deleted successfully", override, name),
+	})
+}
+
+// @Summary		Create service
+// @Description	Render a service (and optional timer) from a structured spec to /etc/systemd/system, verify it, reload systemd and optionally enable and start it. The files are removed again if enabling or starting fails. When started, the response contains the start job, which can be followed at /systemd/jobs/{id}
+// @Tags			systemd
+// @Accept			json
+// @Produce		json
+// @Param			spec	body		types.SystemdUnitSpec	true	"Service definition"
+// @Success		201		{object}	types.SystemdUnitCreated
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		409		{object}	types.ErrorResponse
+// @Failure		422		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd [post]
+func (h *SystemdHandler) createService(c *gin.Context) {
+	if !requireSystemScope(c, "Creating services") {
+		return
+	}
+
+	var spec types.SystemdUnitSpec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	created, err := h.service.CreateUnit(spec)
+	if h.handleUnitFileError(c, err, spec.Name, "create service") {
+		return
+	}
+
+	h.logger.Info("successfully created service",
+		"service", created.Name,
+		"timer", spec.Timer != nil,
+		"enable", spec.Enable,
+		"start", spec.Start)
+	c.JSON(http.StatusCreated, created)
+}
+
+// @Summary		Delete service
+// @Description	Stop, disable and remove a service created through Sirberus, including its timer. Waits until the stop jobs have finished, which may take up to the TimeoutStopSec of the units. Fails if a stop job has not finished shortly after that.
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Service name"
+// @Success		200		{object}	types.Message
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		403		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name} [delete]
+func (h *SystemdHandler) deleteService(c *gin.Context) {
+	if !requireSystemScope(c, "Deleting services") {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	err := h.service.DeleteUnit(name)
+	if h.handleUnitFileError(c, err, name, "delete service") {
+		return
+	}
+
+	h.logger.Info("successfully deleted service",
+		"service", name)
+	c.JSON(http.StatusOK, types.Message{
+		Message: fmt.Sprintf("Service %s deleted successfully", name),
+	})
+}
+
+// handleUnitFileError maps invalid names and failed verification of unit file changes
+// to client errors and falls back to common.HandleError otherwise
+func (h *SystemdHandler) handleUnitFileError(c *gin.Context, err error, name string, operation string) bool {
+	switch {
+	case errors.Is(err, systemd.ErrInvalidUnitFileName), errors.Is(err, systemd.ErrInvalidUnitSpec):
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return true
+	case errors.Is(err, systemd.ErrUnitNotManaged):
+		c.JSON(http.StatusForbidden, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return true
+	case errors.Is(err, systemd.ErrUnitExists):
+		c.JSON(http.StatusConflict, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return true
+	case errors.Is(err, systemd.ErrVerificationFailed):
+		h.logger.Warn(fmt.Sprintf("failed to %s", operation),
+			"id", name,
+			"error", err)
+		c.JSON(http.StatusUnprocessableEntity, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return true
+	}
+	return common.HandleError(c, err, name, operation, h.logger, "Unit file for %s not found")
+}
+
+// @Summary		Get service dependencies
+// @Description	Get the dependency graph of a systemd unit with the state of every unit. Forward traversal follows Requires, Wants, BindsTo, PartOf and After; reverse traversal follows RequiredBy, WantedBy, BoundBy, ConsistsOf and Before.
+// @Tags			systemd
+// @Produce		json
+// @Param			name		path		string	true	"Unit name (e.g. nginx or backup.timer)"
+// @Param			direction	query		string	false	"Traversal direction"	Enums(forward, reverse)	default(forward)
+// @Param			depth		query		integer	false	"Maximum traversal depth"	minimum(1)	maximum(10)	default(2)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200			{object}	types.SystemdDependencyGraph
+// @Failure		400			{object}	types.ErrorResponse
+// @Failure		404			{object}	types.ErrorResponse
+// @Failure		500			{object}	types.ErrorResponse
+// @Router			/systemd/{name}/dependencies [get]
+func (h *SystemdHandler) getServiceDependencies(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+
+	direction := c.DefaultQuery("direction", "forward")
+	if !systemd.IsValidDependencyDirection(direction) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid direction: %s", direction),
+		})
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", "2"))
+	if err != nil || depth < 1 || depth > systemd.MaxDependencyDepth {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Depth must be between 1 and %d", systemd.MaxDependencyDepth),
+		})
+		return
+	}
+
+	graph, err := service.GetUnitDependencies(name, direction, depth)
+	if common.HandleError(c, err, name, "get dependencies of service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully got service dependencies",
+		"service", name,
+		"direction", direction,
+		"nodes", len(graph.Nodes))
+	c.JSON(http.StatusOK, graph)
+}
+
+// @Summary		Get service security
+// @Description	Assess the sandboxing of a systemd service like systemd-analyze security. Every hardening setting, such as ProtectSystem, PrivateTmp, NoNewPrivileges, CapabilityBoundingSet and RestrictAddressFamilies, is checked and weighted into an exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed).
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Service name (e.g. nginx)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdSecurityAssessment
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/security [get]
+func (h *SystemdHandler) getServiceSecurity(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := getUnitName(c.Param("name"))
+	assessment, err := service.GetServiceSecurity(name)
+	if errors.Is(err, systemd.ErrNotService) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if common.HandleError(c, err, name, "get security of service", h.logger, "Service %s not found") {
+		return
+	}
+
+	h.logger.Info("successfully got service security",
+		"service", name,
+		"exposure", assessment.Exposure)
+	c.JSON(http.StatusOK, assessment)
+}
+
+// @Summary		List systemd services
+// @Description	Get a list of systemd units, filtered by unit type
+// @Tags			systemd
+// @Produce		json
+// @Param			type	query		string	false	"Unit type to list, or 'all' for every type"	Enums(all, service, socket, target, device, mount, automount, swap, timer, path, slice, scope)	default(service)
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SystemdServiceList
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd [get]
+func (h *SystemdHandler) listServices(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	unitType := c.DefaultQuery("type", "service")
+	if unitType == "all" {
+		unitType = ""
+	} else if !systemd.IsValidUnitType(unitType) {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid unit type: %s", unitType),
+		})
+		return
+	}
+
+	services, err := service.ListUnits(unitType)
+	if common.HandleError(c, err, "", "list services", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully listed services", "type", unitType, "count", len(services.Services))
+	c.JSON(http.StatusOK, services)
+}
+
+// @Summary		List systemd timers
+// @Description	Get a list of all systemd timers with their next and last elapse times
+// @Tags			systemd
+// @Produce		json
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200	{object}	types.SystemdTimerList
+// @Failure		400	{object}	types.ErrorResponse
+// @Failure		500	{object}	types.ErrorResponse
+// @Router			/systemd/timers [get]
+func (h *SystemdHandler) listTimers(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	timers, err := service.ListTimers()
+	if common.HandleError(c, err, "", "list timers", h.logger, "") {
+		return
+	}
+
+	h.logger.Info("successfully listed timers", "count", timers.Count)
+	c.JSON(http.StatusOK, timers)
+}
+
+// @Summary		Trigger timer
+// @Description	Start the unit activated by a systemd timer immediately without waiting for its schedule. Returns the queued start job of that unit, which can be followed at /systemd/jobs/{id}
+// @Tags			systemd
+// @Produce		json
+// @Param			name	path		string	true	"Timer name (e.g. backup or backup.timer)"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		202		{object}	types.SystemdJob
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		404		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.ErrorResponse
+// @Router			/systemd/{name}/trigger [post]
+func (h *SystemdHandler) triggerTimer(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	name := c.Param("name")
+	if systemd.UnitType(name) == "" {
+		name += ".timer"
+	}
+
+	if systemd.UnitType(name) != "timer" {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Unit %s is not a timer", name),
+		})
+		return
+	}
+
+	job, err := service.TriggerTimer(name)
+	if common.HandleError(c, err, name, "trigger timer", h.logger, "Timer %s not found") {
+		return
+	}
+
+	h.logger.Info("queued start job for timer",
+		"timer", name,
+		"unit", job.Unit,
+		"job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary		Run transient unit
+// @Description	Run a command as a transient service or scope with resource limits, like systemd-run, and stream its output. The final status is sent as an "exit" event.
+// @Tags			systemd, sse
+// @Accept			json
+// @Produce		text/event-stream
+// @Param			command	body		types.SystemdRunRequest	true	"Command to run"
+// @Param			scope	query		string	false	"Systemd manager scope"	Enums(system, user)	default(system)
+// @Param			uid		query		integer	false	"UID of the user manager (defaults to the UID Sirberus runs as)"
+// @Success		200		{object}	types.SSEvent
+// @Failure		400		{object}	types.ErrorResponse
+// @Failure		500		{object}	types.SSEvent
+// @Router			/systemd/run [post]
+func (h *SystemdHandler) runTransientUnit(c *gin.Context) {
+	service, ok := h.scopedService(c)
+	if !ok {
+		return
+	}
+
+	var runReq types.SystemdRunRequest
+	if err := c.ShouldBindJSON(&runReq); err != nil {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: fmt.Sprintf("Invalid request: %s", err.Error()),
+		})
+		return
+	}
+
+	if len(runReq.Command) == 0 || runReq.Command[0] == "" {
+		c.JSON(http.StatusBadRequest, types.ErrorResponse{
+			Error: "Command cannot be empty",
+		})
+		return
+	}
+	command := strings.Join(runReq.Command, " ")
+
+	common.SetupSSE(c)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	outputCh, errCh, resultCh := service.RunTransientUnit(ctx, runReq)
+
+	h.logger.Info("running command as transient unit",
+		"command", command,
+		"scope", runReq.Scope)
+
+	common.HandleStreamingOutput(ctx, c, outputCh, errCh, command, h.logger)
+
+	select {
+	case result, ok := <-resultCh:
+		if !ok {
+			return
+		}
+		h.logger.Info("transient unit finished",
+			"unit", result.Unit,
+			"result", result.Result,
+			"status", result.Status)
+		exitEvent := types.SSEvent{
+			Type:    "exit",
+			Content: result,
+		}
+		c.SSEvent(exitEvent.Type, exitEvent.Content)
+		c.Writer.Flush()
+	case <-ctx.Done():
+	}
 }
@@ -325,53 +325,14 @@ func (s *ContainerService) RestartContainer(ctx context.Context, id string) erro
 	return cli.ContainerRestart(ctx, id, container.StopOptions{Timeout: &timeoutSeconds})
 }
 
-func (s *ContainerService) StreamContainerLogs(ctx context.Context, id string, follow bool, numLines int) (<-chan string, <-chan error) {
-	logCh := make(chan string)
-	errCh := make(chan error, 1)
-
-	go func() {
-		defer close(logCh)
-		defer close(errCh)
-
-		cli, err := s.createClient(ctx)
-		if err != nil {
-			errCh <- err
-			return
-		}
-		defer cli.Close()
-
-		options := container.LogsOptions{
-			ShowStdout: true,
-			ShowStderr: true,
-			Follow:     follow,
-			Tail:       strconv.Itoa(numLines),
-			Timestamps: true,
-		}
-
-		logs, err := cli.ContainerLogs(ctx, id, options)
-		if err != nil {
-			errCh <- fmt.Errorf("failed to get container logs: %w", err)
-			return
-		}
-		defer logs.Close()
-
-		// Process logs and send to channel
-		scanner := bufio.NewScanner(logs)
-		for scanner.Scan() {
-			line := scanner.Text()
-			select {
-			case logCh <- line:
-			case <-ctx.Done():
-				return
-			}
-		}
-
-		if err := scanner.Err(); err != nil {
-			errCh <- fmt.Errorf("error reading logs: %w", err)
-		}
-	}()
+func (s *ContainerService) KillContainer(ctx context.Context, id string, signal string) error {
+	cli, err := s.createClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
 
-	return logCh, errCh
+	return cli.ContainerKill(ctx, id, signal)
 }
 
 func (s *ContainerService) ExecInContainer(ctx context.Context, id string, command string) (<-chan string, <-chan error) {
@@ -2,9 +2,13 @@ package container
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
 )
 
 // TestWithRealContainer runs a comprehensive test with a real Docker container
@@ -193,7 +197,7 @@ func TestWithRealContainer(t *testing.T) {
 		defer cancel()
 
 		// Stream logs without following
-		logCh, errCh := s.StreamContainerLogs(ctx, containerID, false, 5)
+		logCh, errCh := s.StreamContainerLogs(ctx, containerID, false, 5, types.LogFilter{})
 
 		// Collect logs
 		var logs []string
@@ -249,7 +253,7 @@ func TestWithRealContainer(t *testing.T) {
 		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		logCh, errCh = s.StreamContainerLogs(ctx, containerID, true, 1)
+		logCh, errCh = s.StreamContainerLogs(ctx, containerID, true, 1, types.LogFilter{})
 
 		// Just verify we can read from the channel
 		select {
@@ -342,3 +346,66 @@ func TestWithRealContainer(t *testing.T) {
 		}
 	})
 }
+
+// TestLogMessage tests stripping Docker timestamps before grep matching
+func TestLogMessage(t *testing.T) {
+	if got := logMessage("2024-01-02T15:04:05.123456789Z ERROR failed"); got != "ERROR failed" {
+		t.Errorf("logMessage() = %q, want %q", got, "ERROR failed")
+	}
+	if got := logMessage("no-timestamp"); got != "no-timestamp" {
+		t.Errorf("logMessage() = %q, want %q", got, "no-timestamp")
+	}
+	if got := formatLogTime(time.Unix(1700000000, 5)); got != "1700000000.000000005" {
+		t.Errorf("formatLogTime() = %q, want %q", got, "1700000000.000000005")
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	entry := ParseLogLine("2024-01-02T15:04:05.123456789Z ERROR failed")
+	if entry.Time != "2024-01-02T15:04:05.123456789Z" || entry.Message != "ERROR failed" {
+		t.Errorf("ParseLogLine() = %+v, want time and message split", entry)
+	}
+	entry = ParseLogLine("not a timestamp")
+	if entry.Time != "" || entry.Message != "not a timestamp" {
+		t.Errorf("ParseLogLine() = %+v, want the whole line as message", entry)
+	}
+}
+
+func TestLogCursor(t *testing.T) {
+	timestamp := "2024-01-02T15:04:05.123456789Z"
+	decoded, err := decodeLogCursor(encodeLogCursor(timestamp))
+	if err != nil || !decoded.Equal(time.Date(2024, 1, 2, 15, 4, 5, 123456789, time.UTC)) {
+		t.Errorf("decodeLogCursor() = %v, %v, want %s", decoded, err, timestamp)
+	}
+
+	for _, invalid := range []string{"not a cursor!", encodeLogCursor("yesterday")} {
+		if _, err := decodeLogCursor(invalid); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodeLogCursor(%q) error = %v, want ErrInvalidCursor", invalid, err)
+		}
+	}
+}
+
+func TestLogWindow(t *testing.T) {
+	messages := func(entries []types.LogEntry) string {
+		var parts []string
+		for _, entry := range entries {
+			parts = append(parts, entry.Message)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	for count, expected := range map[int]string{
+		2: "0 1",
+		3: "0 1 2",
+		4: "1 2 3",
+		7: "4 5 6",
+	} {
+		window := newLogWindow(3)
+		for i := 0; i < count; i++ {
+			window.add(types.LogEntry{Message: strconv.Itoa(i)})
+		}
+		if got := messages(window.ordered()); got != expected {
+			t.Errorf("logWindow after %d entries = %q, want %q", count, got, expected)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ErrInvalidCursor is returned for log history cursors that were not issued by Sirberus
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// StreamContainerLogs retrieves the last numLines log lines of a container matching filter and,
+// if follow is set and no end time is given, keeps streaming new lines as they arrive.
+// Priority and boot filters only apply to the journal and are ignored.
+func (s *ContainerService) StreamContainerLogs(ctx context.Context, id string, follow bool, numLines int, filter types.LogFilter) (<-chan string, <-chan error) {
+	options := logsOptions(filter)
+	options.Follow = follow && filter.Until.IsZero()
+	options.Tail = strconv.Itoa(numLines)
+
+	return streamLogs(ctx, s, id, options, filter, func(line string) string { return line })
+}
+
+// ExportContainerLogs sends all log lines of a container matching filter, oldest first
+func (s *ContainerService) ExportContainerLogs(ctx context.Context, id string, filter types.LogFilter) (<-chan types.LogEntry, <-chan error) {
+	return streamLogs(ctx, s, id, logsOptions(filter), filter, ParseLogLine)
+}
+
+// streamLogs sends the log lines of a container matching filter, converted by convert
+func streamLogs[T any](ctx context.Context, s *ContainerService, id string, options container.LogsOptions, filter types.LogFilter, convert func(line string) T) (<-chan T, <-chan error) {
+	logCh := make(chan T)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(logCh)
+		defer close(errCh)
+
+		cli, err := s.createClient(ctx)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer cli.Close()
+
+		logs, err := s.openLogs(ctx, cli, id, options)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer logs.Close()
+
+		// Process logs and send to channel
+		scanner := bufio.NewScanner(logs)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !filter.Matches(logMessage(line)) {
+				continue
+			}
+			select {
+			case logCh <- convert(line):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			errCh <- fmt.Errorf("error reading logs: %w", err)
+		}
+	}()
+
+	return logCh, errCh
+}
+
+// GetContainerLogHistory returns a page of at most limit log lines of a container matching
+// filter that are older than the before cursor, newer than the after cursor, or the latest ones.
+// Cursors are timestamps, so lines sharing the timestamp of a page boundary may be skipped.
+func (s *ContainerService) GetContainerLogHistory(ctx context.Context, id, before, after string, limit int, filter types.LogFilter) (*types.LogPage, error) {
+	options := logsOptions(filter)
+	switch {
+	case after != "":
+		t, err := decodeLogCursor(after)
+		if err != nil {
+			return nil, err
+		}
+		if since := t.Add(time.Nanosecond); since.After(filter.Since) {
+			options.Since = formatLogTime(since)
+		}
+	case before != "":
+		t, err := decodeLogCursor(before)
+		if err != nil {
+			return nil, err
+		}
+		if until := t.Add(-time.Nanosecond); filter.Until.IsZero() || until.Before(filter.Until) {
+			options.Until = formatLogTime(until)
+		}
+	}
+	// Docker can only tail before grep is applied, so read everything when grepping
+	// and keep the last matches
+	if after == "" && filter.Grep == nil {
+		options.Tail = strconv.Itoa(limit)
+	}
+
+	cli, err := s.createClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	logs, err := s.openLogs(ctx, cli, id, options)
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	window := newLogWindow(limit)
+	scanner := bufio.NewScanner(logs)
+	for scanner.Scan() {
+		entry := ParseLogLine(scanner.Text())
+		if !filter.Matches(entry.Message) {
+			continue
+		}
+		if after != "" && window.full() {
+			break
+		}
+		window.add(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading logs: %w", err)
+	}
+	entries := window.ordered()
+
+	page := &types.LogPage{Entries: entries, After: after}
+	if len(entries) == 0 {
+		return page, nil
+	}
+	// A short page read from the end has reached the start of the log
+	if first := entries[0].Time; first != "" && (after != "" || len(entries) == limit) {
+		page.Before = encodeLogCursor(first)
+	}
+	if last := entries[len(entries)-1].Time; last != "" {
+		page.After = encodeLogCursor(last)
+	}
+
+	return page, nil
+}
+
+// logWindow keeps the last limit log entries in a ring buffer
+type logWindow struct {
+	entries []types.LogEntry
+	// next is the index the next entry overwrites once the window is full
+	next int
+}
+
+func newLogWindow(limit int) *logWindow {
+	return &logWindow{entries: make([]types.LogEntry, 0, limit)}
+}
+
+func (w *logWindow) full() bool {
+	return len(w.entries) == cap(w.entries)
+}
+
+// add appends an entry, replacing the oldest one if the window is full
+func (w *logWindow) add(entry types.LogEntry) {
+	if !w.full() {
+		w.entries = append(w.entries, entry)
+		return
+	}
+	w.entries[w.next] = entry
+	w.next = (w.next + 1) % len(w.entries)
+}
+
+// ordered returns the entries from oldest to newest
+func (w *logWindow) ordered() []types.LogEntry {
+	if w.next == 0 {
+		return w.entries
+	}
+	return append(w.entries[w.next:], w.entries[:w.next]...)
+}
+
+// logsOptions returns the options for reading timestamped logs limited to the time range of filter
+func logsOptions(filter types.LogFilter) container.LogsOptions {
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+	}
+	if !filter.Since.IsZero() {
+		options.Since = formatLogTime(filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		options.Until = formatLogTime(filter.Until)
+	}
+	return options
+}
+
+// openLogs returns the log output of a container. Without a TTY Docker multiplexes
+// stdout and stderr into frames, which are merged back into plain lines.
+func (s *ContainerService) openLogs(ctx context.Context, cli *client.Client, id string, options container.LogsOptions) (io.ReadCloser, error) {
+	info, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	logs, err := cli.ContainerLogs(ctx, id, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container logs: %w", err)
+	}
+	if info.Config != nil && info.Config.Tty {
+		return logs, nil
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		writer.CloseWithError(err)
+	}()
+	return &demuxedLogs{PipeReader: reader, logs: logs}, nil
+}
+
+// demuxedLogs reads demultiplexed container logs and closes the underlying stream with the pipe
+type demuxedLogs struct {
+	*io.PipeReader
+	logs io.ReadCloser
+}
+
+func (d *demuxedLogs) Close() error {
+	d.logs.Close()
+	return d.PipeReader.Close()
+}
+
+// ParseLogLine splits a timestamped Docker log line into a log entry
+func ParseLogLine(line string) types.LogEntry {
+	timestamp, message, ok := strings.Cut(line, " ")
+	if !ok {
+		return types.LogEntry{Message: line}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, timestamp); err != nil {
+		return types.LogEntry{Message: line}
+	}
+	return types.LogEntry{Time: timestamp, Message: message}
+}
+
+// formatLogTime formats a time as the fractional Unix timestamp accepted by the Docker API
+func formatLogTime(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// logMessage strips the timestamp Docker prefixes to every log line
+func logMessage(line string) string {
+	if _, message, ok := strings.Cut(line, " "); ok {
+		return message
+	}
+	return line
+}
+
+// encodeLogCursor turns the timestamp of a log line into an opaque, URL-safe history cursor
+func encodeLogCursor(timestamp string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(timestamp))
+}
+
+// decodeLogCursor returns the time of a history cursor
+func decodeLogCursor(cursor string) (time.Time, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(decoded))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return t, nil
+}
@@ -4,30 +4,67 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Keyruu/sirberus/internal/types"
 	"github.com/coreos/go-systemd/v22/dbus"
 )
 
 type unitOperation func(ctx context.Context, conn *dbus.Conn, name string, mode string, ch chan<- string) (int, error)
 
-func (s *SystemdService) StartUnit(name string) error {
-	return s.executeUnitOperation(name, "start", func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+// unitFileChange is any of the change types go-systemd returns for unit file operations
+type unitFileChange interface {
+	dbus.EnableUnitFileChange | dbus.DisableUnitFileChange | dbus.MaskUnitFileChange | dbus.UnmaskUnitFileChange
+}
+
+type unitFileOperation[T unitFileChange] func(ctx context.Context, conn *dbus.Conn, name string) ([]T, error)
+
+// IsValidJobMode reports whether mode is a job mode accepted for unit operations.
+// An empty mode is valid and defaults to "replace".
+func IsValidJobMode(mode string) bool {
+	switch mode {
+	case "", jobModeReplace, jobModeFail, jobModeIsolate, jobModeIgnoreDependencies:
+		return true
+	}
+	return false
+}
+
+func (s *SystemdService) StartUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "start", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
 		return conn.StartUnitContext(ctx, name, mode, ch)
 	})
 }
 
-func (s *SystemdService) StopUnit(name string) error {
-	return s.executeUnitOperation(name, "stop", func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+func (s *SystemdService) StopUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "stop", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
 		return conn.StopUnitContext(ctx, name, mode, ch)
 	})
 }
 
-func (s *SystemdService) RestartUnit(name string) error {
-	return s.executeUnitOperation(name, "restart", func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+func (s *SystemdService) RestartUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "restart", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
 		return conn.RestartUnitContext(ctx, name, mode, ch)
 	})
 }
 
-func (s *SystemdService) executeUnitOperation(name, operation string, fn unitOperation) error {
+func (s *SystemdService) ReloadUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "reload", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+		return conn.ReloadUnitContext(ctx, name, mode, ch)
+	})
+}
+
+func (s *SystemdService) TryRestartUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "try-restart", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+		return conn.TryRestartUnitContext(ctx, name, mode, ch)
+	})
+}
+
+func (s *SystemdService) ReloadOrRestartUnit(name, mode string) (*types.SystemdJob, error) {
+	return s.executeUnitOperation(name, "reload-or-restart", mode, func(ctx context.Context, conn *dbus.Conn, name, mode string, ch chan<- string) (int, error) {
+		return conn.ReloadOrRestartUnitContext(ctx, name, mode, ch)
+	})
+}
+
+// ResetFailedUnit clears the failed state and restart counter of a unit
+func (s *SystemdService) ResetFailedUnit(name string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
 	defer cancel()
 
@@ -37,16 +74,143 @@ func (s *SystemdService) executeUnitOperation(name, operation string, fn unitOpe
 	}
 	defer conn.Close()
 
-	ch := make(chan string)
-	_, err = fn(ctx, conn, name, jobModeReplace, ch)
+	if err := conn.ResetFailedUnitContext(ctx, name); err != nil {
+		return fmt.Errorf("failed to reset-failed unit %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// IsValidKillTarget reports whether target selects processes that can be signalled.
+// An empty target is valid and defaults to "all".
+func IsValidKillTarget(target string) bool {
+	switch target {
+	case "", killTargetMain, killTargetControl, killTargetAll:
+		return true
+	}
+	return false
+}
+
+// KillUnit sends a signal to the main, control or all processes of a unit
+func (s *SystemdService) KillUnit(name, target string, signal int32) error {
+	if !IsValidKillTarget(target) {
+		return fmt.Errorf("invalid kill target %q", target)
+	}
+	if target == "" {
+		target = killTargetAll
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to %s unit %s: %w", operation, name, err)
+		return err
 	}
+	defer conn.Close()
 
-	result := <-ch
-	if result != jobResultDone {
-		return fmt.Errorf("failed to %s unit %s: job result was %s", operation, name, result)
+	if err := conn.KillUnitWithTarget(ctx, name, dbus.Who(target), signal); err != nil {
+		return fmt.Errorf("failed to kill unit %s: %w", name, err)
 	}
 
 	return nil
 }
+
+// executeUnitOperation queues a job for a unit and returns it without waiting for
+// the result, which is tracked in the background
+func (s *SystemdService) executeUnitOperation(name, operation, mode string, fn unitOperation) (*types.SystemdJob, error) {
+	if !IsValidJobMode(mode) {
+		return nil, fmt.Errorf("invalid job mode %q", mode)
+	}
+	if mode == "" {
+		mode = jobModeReplace
+	}
+
+	// The connection has to outlive the request to receive the JobRemoved signal
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), jobWaitTimeout)
+	conn, err := s.newConnection(waitCtx)
+	if err != nil {
+		waitCancel()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(waitCtx, defaultUnitTimeout)
+	defer cancel()
+
+	// Buffered so the signal dispatcher never blocks once nobody waits anymore
+	ch := make(chan string, 1)
+	id, err := fn(ctx, conn, name, mode, ch)
+	if err != nil {
+		conn.Close()
+		waitCancel()
+		return nil, fmt.Errorf("failed to %s unit %s: %w", operation, name, err)
+	}
+
+	job := types.SystemdJob{
+		ID:    uint32(id),
+		Unit:  name,
+		Type:  operation,
+		State: jobStateWaiting,
+	}
+	s.trackJob(waitCtx, waitCancel, conn, job, ch)
+
+	return &job, nil
+}
+
+func (s *SystemdService) EnableUnit(name string) ([]types.SystemdUnitFileChange, error) {
+	return executeUnitFileOperation(s, name, "enable", func(ctx context.Context, conn *dbus.Conn, name string) ([]dbus.EnableUnitFileChange, error) {
+		_, changes, err := conn.EnableUnitFilesContext(ctx, []string{name}, false, false)
+		return changes, err
+	})
+}
+
+func (s *SystemdService) DisableUnit(name string) ([]types.SystemdUnitFileChange, error) {
+	return executeUnitFileOperation(s, name, "disable", func(ctx context.Context, conn *dbus.Conn, name string) ([]dbus.DisableUnitFileChange, error) {
+		return conn.DisableUnitFilesContext(ctx, []string{name}, false)
+	})
+}
+
+func (s *SystemdService) MaskUnit(name string) ([]types.SystemdUnitFileChange, error) {
+	return executeUnitFileOperation(s, name, "mask", func(ctx context.Context, conn *dbus.Conn, name string) ([]dbus.MaskUnitFileChange, error) {
+		return conn.MaskUnitFilesContext(ctx, []string{name}, false, false)
+	})
+}
+
+func (s *SystemdService) UnmaskUnit(name string) ([]types.SystemdUnitFileChange, error) {
+	return executeUnitFileOperation(s, name, "unmask", func(ctx context.Context, conn *dbus.Conn, name string) ([]dbus.UnmaskUnitFileChange, error) {
+		return conn.UnmaskUnitFilesContext(ctx, []string{name}, false)
+	})
+}
+
+// executeUnitFileOperation runs a unit file operation and reloads the daemon
+// so that the new symlinks are picked up by the manager
+func executeUnitFileOperation[T unitFileChange](s *SystemdService, name, operation string, fn unitFileOperation[T]) ([]types.SystemdUnitFileChange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	changes, err := fn(ctx, conn, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s unit %s: %w", operation, name, err)
+	}
+
+	if err := conn.ReloadContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to reload systemd after %s of unit %s: %w", operation, name, err)
+	}
+
+	return convertUnitFileChanges(changes), nil
+}
+
+// convertUnitFileChanges converts the symlink changes reported by systemd
+func convertUnitFileChanges[T unitFileChange](changes []T) []types.SystemdUnitFileChange {
+	result := make([]types.SystemdUnitFileChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, types.SystemdUnitFileChange(change))
+	}
+	return result
+}
@@ -0,0 +1,211 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// unitTiming holds the monotonic activation timestamps of a unit in microseconds.
+// Monotonic timestamps are used because the wall clock is often set during boot.
+type unitTiming struct {
+	// InactiveExitTimestampMonotonic, when the unit started activating
+	activating uint64
+	// ActiveEnterTimestampMonotonic, when the unit became active
+	activated uint64
+	// ExecMainStartTimestampMonotonic, when the main process of a service was started
+	execMainStart uint64
+	// Units ordered before the unit
+	after []string
+}
+
+// bootTimes holds the monotonic timestamps of the manager in microseconds
+type bootTimes struct {
+	userspace uint64
+	finish    uint64
+}
+
+// AnalyzeBoot returns the activation time of every unit, slowest first, and the critical
+// chain to target like systemd-analyze blame and critical-chain
+func (s *SystemdService) AnalyzeBoot(target string) (*types.SystemdBootAnalysis, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), analyzeTimeout)
+	defer cancel()
+
+	boot, err := s.getBootTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Resolve aliases such as default.target to the unit they point to
+	props, err := conn.GetUnitPropertiesContext(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get properties of unit %s: %w", target, err)
+	}
+	if getStringProperty(props, "LoadState") == "not-found" {
+		return nil, fmt.Errorf("unit %s not found", target)
+	}
+	if id := getStringProperty(props, "Id"); id != "" {
+		target = id
+	}
+
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	timings := make(map[string]unitTiming, len(units))
+	for _, unit := range units {
+		props, err := conn.GetUnitPropertiesContext(ctx, unit.Name)
+		if err != nil {
+			s.logger.Warn("failed to get unit timestamps",
+				"unit", unit.Name,
+				"error", err)
+			continue
+		}
+
+		timing := unitTiming{
+			activating: getUint64Property(props, "InactiveExitTimestampMonotonic"),
+			activated:  getUint64Property(props, "ActiveEnterTimestampMonotonic"),
+			after:      getStringArrayProperty(props, "After"),
+		}
+		if UnitType(unit.Name) == "service" {
+			if prop, err := conn.GetServicePropertyContext(ctx, unit.Name, "ExecMainStartTimestampMonotonic"); err == nil {
+				timing.execMainStart, _ = prop.Value.Value().(uint64)
+			}
+		}
+		timings[unit.Name] = timing
+	}
+
+	analysis := &types.SystemdBootAnalysis{
+		Finished:      boot.finish > 0,
+		Blame:         buildBlame(timings, boot),
+		CriticalChain: buildCriticalChain(target, timings, boot, map[string]bool{}),
+	}
+	if boot.finish > boot.userspace {
+		analysis.UserspaceTime = usecToMillis(boot.finish - boot.userspace)
+	}
+
+	return analysis, nil
+}
+
+// getBootTimes reads the boot timestamps of the manager. go-systemd only returns
+// manager properties as strings, so they are read directly.
+func (s *SystemdService) getBootTimes(ctx context.Context) (bootTimes, error) {
+	conn, err := s.dialBus(ctx)
+	if err != nil {
+		return bootTimes{}, fmt.Errorf("failed to connect to systemd: %w", err)
+	}
+	defer conn.Close()
+
+	manager := conn.Object("org.freedesktop.systemd1", godbus.ObjectPath("/org/freedesktop/systemd1"))
+	var boot bootTimes
+	for property, value := range map[string]*uint64{
+		"UserspaceTimestampMonotonic": &boot.userspace,
+		"FinishTimestampMonotonic":    &boot.finish,
+	} {
+		var variant godbus.Variant
+		err := manager.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0,
+			"org.freedesktop.systemd1.Manager", property).Store(&variant)
+		if err != nil {
+			return bootTimes{}, fmt.Errorf("failed to get %s: %w", property, err)
+		}
+		*value, _ = variant.Value().(uint64)
+	}
+
+	return boot, nil
+}
+
+// buildBlame returns the units that took time to activate, slowest first
+func buildBlame(timings map[string]unitTiming, boot bootTimes) []types.SystemdUnitTiming {
+	blame := []types.SystemdUnitTiming{}
+	for name, timing := range timings {
+		if timing.activating == 0 || timing.activated <= timing.activating {
+			continue
+		}
+		blame = append(blame, unitTimingOf(name, timing, boot))
+	}
+
+	sort.Slice(blame, func(i, j int) bool {
+		if blame[i].ActivationTime != blame[j].ActivationTime {
+			return blame[i].ActivationTime > blame[j].ActivationTime
+		}
+		return blame[i].Name < blame[j].Name
+	})
+	return blame
+}
+
+// buildCriticalChain follows the After dependencies of a unit to the ones that became active
+// last before the boot finished, which are the ones the unit had to wait for
+func buildCriticalChain(name string, timings map[string]unitTiming, boot bootTimes, visited map[string]bool) types.SystemdCriticalChainNode {
+	timing := timings[name]
+	node := types.SystemdCriticalChainNode{
+		SystemdUnitTiming: unitTimingOf(name, timing, boot),
+		Children:          []types.SystemdCriticalChainNode{},
+	}
+	visited[name] = true
+
+	after := append([]string(nil), timing.after...)
+	sort.Strings(after)
+
+	var latest uint64
+	for _, dependency := range after {
+		if activated := chainActivation(timings[dependency], boot); activated > latest {
+			latest = activated
+		}
+	}
+	if latest == 0 {
+		return node
+	}
+
+	for _, dependency := range after {
+		if chainActivation(timings[dependency], boot) != latest {
+			continue
+		}
+		if visited[dependency] {
+			node.Children = append(node.Children, types.SystemdCriticalChainNode{
+				SystemdUnitTiming: unitTimingOf(dependency, timings[dependency], boot),
+				Children:          []types.SystemdCriticalChainNode{},
+			})
+			continue
+		}
+		node.Children = append(node.Children, buildCriticalChain(dependency, timings, boot, visited))
+	}
+
+	return node
+}
+
+// chainActivation returns when a unit became active if that happened during the boot, or 0
+func chainActivation(timing unitTiming, boot bootTimes) uint64 {
+	if boot.finish > 0 && timing.activated > boot.finish {
+		return 0
+	}
+	return timing.activated
+}
+
+// unitTimingOf converts the timestamps of a unit into durations in milliseconds
+func unitTimingOf(name string, timing unitTiming, boot bootTimes) types.SystemdUnitTiming {
+	result := types.SystemdUnitTiming{Name: name}
+	if timing.activating > 0 && timing.activated > timing.activating {
+		result.ActivationTime = usecToMillis(timing.activated - timing.activating)
+	}
+	if timing.activating > 0 && timing.execMainStart > timing.activating && timing.execMainStart <= timing.activated {
+		result.ExecMainStartTime = usecToMillis(timing.execMainStart - timing.activating)
+	}
+	if timing.activated > boot.userspace {
+		result.ActivatedAt = usecToMillis(timing.activated - boot.userspace)
+	}
+	return result
+}
+
+func usecToMillis(usec uint64) int64 {
+	return int64(usec / microsecondsPerMillisecond)
+}
@@ -0,0 +1,105 @@
+package systemd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// journalBoot is a boot in the JSON output of journalctl --list-boots
+type journalBoot struct {
+	Index      int    `json:"index"`
+	BootID     string `json:"boot_id"`
+	FirstEntry int64  `json:"first_entry"`
+	LastEntry  int64  `json:"last_entry"`
+}
+
+// ListBoots returns the boots recorded in the journal like journalctl --list-boots
+func (s *SystemdService) ListBoots() (*types.SystemdBootList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), listBootsTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "journalctl", "--list-boots", "--output", "json", "--no-pager")
+	// Older journalctl versions ignore --output here, so make their text output parseable
+	cmd.Env = append(os.Environ(), "TZ=UTC")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return nil, fmt.Errorf("failed to list boots: %s", text)
+		}
+		return nil, fmt.Errorf("failed to list boots: %w", err)
+	}
+
+	boots, err := parseBootList(output)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SystemdBootList{
+		Boots: boots,
+		Count: len(boots),
+	}, nil
+}
+
+// parseBootList parses the JSON or, for journalctl before version 251, text output
+// of journalctl --list-boots
+func parseBootList(output []byte) ([]types.SystemdBoot, error) {
+	output = bytes.TrimSpace(output)
+	boots := []types.SystemdBoot{}
+
+	if bytes.HasPrefix(output, []byte("[")) {
+		var entries []journalBoot
+		if err := json.Unmarshal(output, &entries); err != nil {
+			return nil, fmt.Errorf("invalid boot list: %w", err)
+		}
+		for _, entry := range entries {
+			boots = append(boots, types.SystemdBoot{
+				Index:      entry.Index,
+				ID:         entry.BootID,
+				FirstEntry: time.UnixMicro(entry.FirstEntry).Format(logTimeFormat),
+				LastEntry:  time.UnixMicro(entry.LastEntry).Format(logTimeFormat),
+			})
+		}
+		return boots, nil
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		// Rows look like "-1 <boot id> Mon 2024-01-01 10:00:00 UTC—Mon 2024-01-01 12:00:00 UTC";
+		// newer versions separate the times with spaces and print a header
+		fields := strings.Fields(strings.ReplaceAll(line, "—", " "))
+		if len(fields) != 10 {
+			continue
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		first, err := time.Parse(bootListTimeFormat, strings.Join(fields[2:6], " "))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boot list entry %q: %w", line, err)
+		}
+		last, err := time.Parse(bootListTimeFormat, strings.Join(fields[6:10], " "))
+		if err != nil {
+			return nil, fmt.Errorf("invalid boot list entry %q: %w", line, err)
+		}
+		boots = append(boots, types.SystemdBoot{
+			Index:      index,
+			ID:         fields[1],
+			FirstEntry: first.Local().Format(logTimeFormat),
+			LastEntry:  last.Local().Format(logTimeFormat),
+		})
+	}
+
+	return boots, nil
+}
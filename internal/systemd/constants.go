@@ -8,14 +8,106 @@ const (
 	journalWaitTimeout = time.Second
 
 	// Job mode and result constants
-	jobModeReplace = "replace"
-	jobResultDone  = "done"
+	jobModeReplace            = "replace"
+	jobModeFail               = "fail"
+	jobModeIsolate            = "isolate"
+	jobModeIgnoreDependencies = "ignore-dependencies"
+	jobResultDone             = "done"
+
+	// Asynchronous job tracking
+	jobStateWaiting  = "waiting"
+	jobStateFinished = "finished"
+	jobWaitTimeout   = 30 * time.Minute
+	jobRetention     = 10 * time.Minute
+	jobPollInterval  = 500 * time.Millisecond
+	// Time a stop job may take beyond the unit's TimeoutStopSec before giving up on it
+	stopJobTimeoutMargin = 10 * time.Second
+
+	// Kill targets
+	killTargetMain    = "main"
+	killTargetControl = "control"
+	killTargetAll     = "all"
+
+	// Manager scopes
+	scopeSystem = "system"
+	scopeUser   = "user"
+	// Bus socket of a per-user manager
+	userBusPathFormat = "/run/user/%d/bus"
+
+	// Load state reported for installed unit files that systemd has not loaded
+	loadStateNotLoaded = "not-loaded"
+
+	// Unit file origins
+	unitFileOriginVendor  = "vendor"
+	unitFileOriginAdmin   = "admin"
+	unitFileOriginRuntime = "runtime"
+	unitFileOriginOther   = "other"
+
+	// Unit file states of files that only point at another unit
+	unitFileStateAlias         = "alias"
+	unitFileStateLinked        = "linked"
+	unitFileStateLinkedRuntime = "linked-runtime"
+
+	// Directory for administrator unit files and drop-ins
+	adminUnitDir = "/etc/systemd/system"
+	// Header written to unit files created by Sirberus, required before deleting a unit
+	managedUnitHeader = "# Managed by Sirberus"
+	// Timeout for systemd-analyze verify
+	verifyTimeout = 30 * time.Second
+
+	// Settings for transient units started by Sirberus
+	transientUnitPrefix       = "sirberus-run-"
+	transientPollInterval     = 500 * time.Millisecond
+	transientLogFlushDuration = time.Second
+	maxTransientLogLines      = 10000
+
+	// Unit state events
+	unitEventBuffer = 256
+
+	// Dependency graph traversal
+	dependencyDirectionForward = "forward"
+	dependencyDirectionReverse = "reverse"
+	MaxDependencyDepth         = 10
+	maxDependencyNodes         = 500
+
+	// Boot analysis
+	analyzeTimeout       = 30 * time.Second
+	DefaultAnalyzeTarget = "default.target"
+	// Timeout for assessing the security of all services
+	securityReportTimeout = 30 * time.Second
+
+	// Failed units
+	failedUnitsTimeout = 30 * time.Second
+	// Number of log lines of a failed run to include
+	failureLogLines = 20
+
+	// Journal reading
+	journalRestartDelay = 500 * time.Millisecond
+	// Restarts back off up to this delay while journalctl keeps failing immediately
+	maxJournalRestartDelay = 30 * time.Second
+	// Consecutive immediate journalctl failures after which following gives up
+	maxJournalFailures  = 5
+	maxJournalEntrySize = 4 * 1024 * 1024
+	// Time format of log entries, RFC3339 with microseconds as stored in the journal
+	logTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	// Timeout for listing boots, which reads the whole journal
+	listBootsTimeout = 30 * time.Second
+	// Time format of the text output of journalctl --list-boots
+	bootListTimeFormat = "Mon 2006-01-02 15:04:05 MST"
 
 	// Journal field names
-	journalMessageField = "MESSAGE"
-	journalUnitField    = "_SYSTEMD_UNIT"
+	journalMessageField    = "MESSAGE"
+	journalUnitField       = "_SYSTEMD_UNIT"
+	journalUserUnitField   = "_SYSTEMD_USER_UNIT"
+	journalCursorField     = "__CURSOR"
+	journalRealtimeField   = "__REALTIME_TIMESTAMP"
+	journalPriorityField   = "PRIORITY"
+	journalPIDField        = "_PID"
+	journalIdentifierField = "SYSLOG_IDENTIFIER"
+	journalBootIDField     = "_BOOT_ID"
 
 	// Time conversion constants
-	microsecondsPerSecond     = 1000000
-	nanosecondsPerMicrosecond = 1000
+	microsecondsPerSecond      = 1000000
+	microsecondsPerMillisecond = 1000
+	nanosecondsPerMicrosecond  = 1000
 )
@@ -0,0 +1,107 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// dependencyProperties lists the unit properties followed in each traversal direction
+var dependencyProperties = map[string][]string{
+	dependencyDirectionForward: {"Requires", "Wants", "BindsTo", "PartOf", "After"},
+	dependencyDirectionReverse: {"RequiredBy", "WantedBy", "BoundBy", "ConsistsOf", "Before"},
+}
+
+// IsValidDependencyDirection reports whether direction is "forward" or "reverse"
+func IsValidDependencyDirection(direction string) bool {
+	_, ok := dependencyProperties[direction]
+	return ok
+}
+
+// GetUnitDependencies walks the dependencies of a unit breadth-first up to depth levels
+// and returns them as a graph with the state of every unit
+func (s *SystemdService) GetUnitDependencies(name, direction string, depth int) (*types.SystemdDependencyGraph, error) {
+	properties, ok := dependencyProperties[direction]
+	if !ok {
+		return nil, fmt.Errorf("invalid dependency direction %q", direction)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	graph := &types.SystemdDependencyGraph{
+		Root:      name,
+		Direction: direction,
+		Depth:     depth,
+	}
+	err = walkDependencies(graph, properties, func(unit string) (map[string]interface{}, error) {
+		return conn.GetUnitPropertiesContext(ctx, unit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// walkDependencies fills graph breadth-first from its root with the units and edges found
+// through properties. Once maxDependencyNodes units are reached the graph is truncated:
+// edges to units that did not fit are left out, so every edge ends at a node of the graph.
+func walkDependencies(graph *types.SystemdDependencyGraph, properties []string, unitProperties func(unit string) (map[string]interface{}, error)) error {
+	graph.Nodes = []types.SystemdDependencyNode{}
+	graph.Edges = []types.SystemdDependencyEdge{}
+
+	visited := map[string]bool{graph.Root: true}
+	queue := []types.SystemdDependencyNode{{Name: graph.Root}}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		props, err := unitProperties(node.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get properties of unit %s: %w", node.Name, err)
+		}
+
+		node.LoadState = getStringProperty(props, "LoadState")
+		node.ActiveState = getStringProperty(props, "ActiveState")
+		node.SubState = getStringProperty(props, "SubState")
+		if node.Depth == 0 && node.LoadState == "not-found" {
+			return fmt.Errorf("unit %s not found", graph.Root)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		if node.Depth >= graph.Depth {
+			continue
+		}
+
+		for _, property := range properties {
+			for _, dependency := range getStringArrayProperty(props, property) {
+				if !visited[dependency] {
+					if len(visited) >= maxDependencyNodes {
+						// The unit never becomes a node, so neither may the edge to it
+						graph.Truncated = true
+						continue
+					}
+					// Every visited unit is queued and becomes a node
+					visited[dependency] = true
+					queue = append(queue, types.SystemdDependencyNode{Name: dependency, Depth: node.Depth + 1})
+				}
+
+				graph.Edges = append(graph.Edges, types.SystemdDependencyEdge{
+					From: node.Name,
+					To:   dependency,
+					Type: property,
+				})
+			}
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
 )
 
 // TestWithNonExistentService tests behavior with a non-existent service
@@ -21,32 +23,86 @@ func TestWithNonExistentService(t *testing.T) {
 	}
 
 	// Test service actions
-	err = s.StartUnit(nonExistentService)
+	_, err = s.StartUnit(nonExistentService, jobModeReplace)
 	if err == nil {
 		t.Error("StartUnit should fail for non-existent service")
 	} else {
 		t.Logf("StartUnit correctly failed: %v", err)
 	}
 
-	err = s.StopUnit(nonExistentService)
+	_, err = s.StopUnit(nonExistentService, jobModeReplace)
 	if err == nil {
 		t.Error("StopUnit should fail for non-existent service")
 	} else {
 		t.Logf("StopUnit correctly failed: %v", err)
 	}
 
-	err = s.RestartUnit(nonExistentService)
+	_, err = s.RestartUnit(nonExistentService, jobModeReplace)
 	if err == nil {
 		t.Error("RestartUnit should fail for non-existent service")
 	} else {
 		t.Logf("RestartUnit correctly failed: %v", err)
 	}
 
+	_, err = s.ReloadUnit(nonExistentService, jobModeReplace)
+	if err == nil {
+		t.Error("ReloadUnit should fail for non-existent service")
+	} else {
+		t.Logf("ReloadUnit correctly failed: %v", err)
+	}
+
+	_, err = s.StartUnit(nonExistentService, "invalid-mode")
+	if err == nil {
+		t.Error("StartUnit should fail for an invalid job mode")
+	} else {
+		t.Logf("StartUnit correctly failed: %v", err)
+	}
+
+	err = s.KillUnit(nonExistentService, "main", 1)
+	if err == nil {
+		t.Error("KillUnit should fail for non-existent service")
+	} else {
+		t.Logf("KillUnit correctly failed: %v", err)
+	}
+
+	err = s.KillUnit(nonExistentService, "invalid-target", 1)
+	if err == nil {
+		t.Error("KillUnit should fail for an invalid target")
+	} else {
+		t.Logf("KillUnit correctly failed: %v", err)
+	}
+
+	// Test job operations
+	if _, err := s.GetJob(4294967295); err == nil {
+		t.Error("GetJob should fail for non-existent job")
+	} else {
+		t.Logf("GetJob correctly failed: %v", err)
+	}
+
+	if err := s.CancelJob(4294967295); err == nil {
+		t.Error("CancelJob should fail for non-existent job")
+	} else {
+		t.Logf("CancelJob correctly failed: %v", err)
+	}
+
+	// Test unit file operations
+	if _, err := s.EnableUnit(nonExistentService); err == nil {
+		t.Error("EnableUnit should fail for non-existent service")
+	} else {
+		t.Logf("EnableUnit correctly failed: %v", err)
+	}
+
+	if _, err := s.DisableUnit(nonExistentService); err == nil {
+		t.Error("DisableUnit should fail for non-existent service")
+	} else {
+		t.Logf("DisableUnit correctly failed: %v", err)
+	}
+
 	// Test streaming logs
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	logCh, errCh := s.StreamServiceLogs(ctx, nonExistentService, false, 5)
+	logCh, errCh := s.StreamServiceLogs(ctx, nonExistentService, false, 5, types.LogFilter{})
 
 	// We need to handle both cases:
 	// 1. An error is sent on the error channel
@@ -54,7 +110,7 @@ func TestWithNonExistentService(t *testing.T) {
 	select {
 	case log, ok := <-logCh:
 		if ok {
-			t.Logf("Unexpectedly received log for non-existent service: %s", log)
+			t.Logf("Unexpectedly received log for non-existent service: %+v", log)
 		} else {
 			t.Log("Log channel closed without error (valid behavior)")
 		}
@@ -93,17 +149,17 @@ func TestInvalidServiceName(t *testing.T) {
 			}
 
 			// Test service actions
-			err = s.StartUnit(name)
+			_, err = s.StartUnit(name, jobModeReplace)
 			if err == nil {
 				t.Errorf("StartUnit should fail for invalid service name: %s", name)
 			}
 
-			err = s.StopUnit(name)
+			_, err = s.StopUnit(name, jobModeReplace)
 			if err == nil {
 				t.Errorf("StopUnit should fail for invalid service name: %s", name)
 			}
 
-			err = s.RestartUnit(name)
+			_, err = s.RestartUnit(name, jobModeReplace)
 			if err == nil {
 				t.Errorf("RestartUnit should fail for invalid service name: %s", name)
 			}
@@ -0,0 +1,116 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// WatchUnitEvents subscribes to systemd PropertiesChanged signals and sends an event
+// whenever the load, active or sub state of a unit changes
+func (s *SystemdService) WatchUnitEvents(ctx context.Context) (<-chan types.SystemdUnitEvent, <-chan error) {
+	eventCh := make(chan types.SystemdUnitEvent)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(eventCh)
+		defer close(errCh)
+
+		conn, err := s.newConnection(ctx)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer conn.Close()
+
+		// Closing the connection ends the subscription, so there is no need to unsubscribe
+		if err := conn.Subscribe(); err != nil {
+			errCh <- fmt.Errorf("failed to subscribe to systemd signals: %w", err)
+			return
+		}
+
+		updateCh := make(chan *dbus.PropertiesUpdate, unitEventBuffer)
+		updateErrCh := make(chan error, 1)
+		conn.SetPropertiesSubscriber(updateCh, updateErrCh)
+
+		// Signals only carry the properties that changed, so keep the last known
+		// state of every unit to fill in the rest and drop unrelated changes
+		units, err := conn.ListUnitsContext(ctx)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to list units: %w", err)
+			return
+		}
+		states := make(map[string]types.SystemdUnitEvent, len(units))
+		for _, unit := range units {
+			states[unit.Name] = types.SystemdUnitEvent{
+				Unit:        unit.Name,
+				LoadState:   unit.LoadState,
+				ActiveState: unit.ActiveState,
+				SubState:    unit.SubState,
+			}
+		}
+
+		for {
+			select {
+			case update := <-updateCh:
+				event, changed := applyUnitStateUpdate(states, update.UnitName, update.Changed)
+				if !changed {
+					continue
+				}
+				event.Timestamp = time.Now().Format(time.RFC3339)
+
+				select {
+				case eventCh <- event:
+				case <-ctx.Done():
+					return
+				}
+
+			case err := <-updateErrCh:
+				s.logger.Warn("dropped unit state updates", "error", err)
+				select {
+				case errCh <- fmt.Errorf("missed unit state changes: %w", err):
+				default:
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return eventCh, errCh
+}
+
+// applyUnitStateUpdate merges changed properties into the known state of a unit and
+// reports whether its load, active or sub state changed
+func applyUnitStateUpdate(states map[string]types.SystemdUnitEvent, unit string, changed map[string]godbus.Variant) (types.SystemdUnitEvent, bool) {
+	previous, known := states[unit]
+	current := previous
+	current.Unit = unit
+
+	for property, field := range map[string]*string{
+		"LoadState":   &current.LoadState,
+		"ActiveState": &current.ActiveState,
+		"SubState":    &current.SubState,
+	} {
+		if variant, ok := changed[property]; ok {
+			if value, ok := variant.Value().(string); ok {
+				*field = value
+			}
+		}
+	}
+
+	if known && current == previous {
+		return current, false
+	}
+	if !known && current.LoadState == "" && current.ActiveState == "" && current.SubState == "" {
+		return current, false
+	}
+
+	states[unit] = current
+	return current, true
+}
@@ -0,0 +1,120 @@
+package systemd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// ListFailedUnits returns all failed units with why they failed and the tail of the log of
+// their failed run, most recent failure first
+func (s *SystemdService) ListFailedUnits() (*types.SystemdFailedUnitList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), failedUnitsTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	units, err := conn.ListUnitsFilteredContext(ctx, []string{"failed"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	list := &types.SystemdFailedUnitList{Units: []types.SystemdFailedUnit{}}
+	for _, unit := range units {
+		failed := types.SystemdFailedUnit{
+			Name:        unit.Name,
+			Description: unit.Description,
+			Type:        UnitType(unit.Name),
+			SubState:    unit.SubState,
+		}
+
+		props, err := conn.GetAllPropertiesContext(ctx, unit.Name)
+		if err != nil {
+			s.logger.Warn("failed to get unit properties",
+				"unit", unit.Name,
+				"error", err)
+		} else {
+			failed.SystemdFailureInfo = s.getFailureInfo(ctx, unit.Name, props)
+		}
+
+		list.Units = append(list.Units, failed)
+	}
+
+	// RFC3339 timestamps in the same zone sort chronologically as strings
+	sort.SliceStable(list.Units, func(i, j int) bool {
+		if list.Units[i].FailedAt != list.Units[j].FailedAt {
+			return list.Units[i].FailedAt > list.Units[j].FailedAt
+		}
+		return list.Units[i].Name < list.Units[j].Name
+	})
+	list.Count = len(list.Units)
+
+	return list, nil
+}
+
+// getFailureInfo returns why a unit last failed and, if it did, the tail of the log of that run
+func (s *SystemdService) getFailureInfo(ctx context.Context, name string, props map[string]interface{}) types.SystemdFailureInfo {
+	info := unitFailureInfo(props)
+	if info.FailedAt == "" {
+		return info
+	}
+
+	invocationID, _ := props["InvocationID"].([]uint8)
+	if len(invocationID) == 0 {
+		return info
+	}
+
+	logs, err := s.collectJournal(ctx, journalQuery{
+		matches: journalInvocationArgs(hex.EncodeToString(invocationID)),
+		lines:   failureLogLines,
+		reverse: true,
+	}, failureLogLines)
+	if err != nil {
+		s.logger.Warn("failed to read failure logs",
+			"unit", name,
+			"error", err)
+		return info
+	}
+	info.FailureLogs = logs
+
+	return info
+}
+
+// unitFailureInfo reads the result of the last run of a unit from its properties. The failure
+// time is only set if the run did not succeed and is when the unit stopped being active.
+func unitFailureInfo(props map[string]interface{}) types.SystemdFailureInfo {
+	info := types.SystemdFailureInfo{
+		Result:         getStringProperty(props, "Result"),
+		ExecMainCode:   exitCodeName(getInt32Property(props, "ExecMainCode")),
+		ExecMainStatus: getInt32Property(props, "ExecMainStatus"),
+		NRestarts:      getUint32Property(props, "NRestarts"),
+	}
+
+	if info.Result != "" && info.Result != "success" {
+		// Failed units enter the failed state through InactiveEnter, while restarting
+		// services never become inactive and only leave the active state
+		stopped := max(getUint64Property(props, "InactiveEnterTimestamp"), getUint64Property(props, "ActiveExitTimestamp"))
+		info.FailedAt = formatTimestamp(stopped)
+	}
+
+	return info
+}
+
+// journalInvocationArgs returns the journalctl matches for the messages of one run of a
+// unit, including the messages the manager logged about it
+func journalInvocationArgs(invocationID string) []string {
+	return []string{
+		"_SYSTEMD_INVOCATION_ID=" + invocationID,
+		"+",
+		"INVOCATION_ID=" + invocationID,
+		"+",
+		"USER_INVOCATION_ID=" + invocationID,
+	}
+}
@@ -1,11 +1,21 @@
 package systemd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
 )
 
 // TestHelperFunctions tests the helper functions for property extraction
@@ -138,3 +148,902 @@ func TestPositionJournalCursor(t *testing.T) {
 		})
 	}
 }
+
+// TestLookupUnitFileState tests resolving unit file states, including template instances
+func TestLookupUnitFileState(t *testing.T) {
+	states := map[string]string{
+		"sshd.service":   "enabled",
+		"getty@.service": "static",
+	}
+
+	testCases := []struct {
+		name     string
+		unit     string
+		expected string
+	}{
+		{name: "Exact match", unit: "sshd.service", expected: "enabled"},
+		{name: "Template instance", unit: "getty@tty1.service", expected: "static"},
+		{name: "Unknown unit", unit: "unknown.service", expected: ""},
+		{name: "Unknown template instance", unit: "foo@bar.service", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := lookupUnitFileState(states, tc.unit); got != tc.expected {
+				t.Errorf("lookupUnitFileState(%s) = %q, want %q", tc.unit, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestIsValidJobMode tests job mode validation for unit operations
+func TestIsValidJobMode(t *testing.T) {
+	for _, mode := range []string{"", "replace", "fail", "isolate", "ignore-dependencies"} {
+		if !IsValidJobMode(mode) {
+			t.Errorf("IsValidJobMode(%q) = false, want true", mode)
+		}
+	}
+
+	for _, mode := range []string{"REPLACE", "flush", "replace-irreversibly; rm -rf /"} {
+		if IsValidJobMode(mode) {
+			t.Errorf("IsValidJobMode(%q) = true, want false", mode)
+		}
+	}
+}
+
+// TestIsValidKillTarget tests kill target validation
+func TestIsValidKillTarget(t *testing.T) {
+	for _, target := range []string{"", "main", "control", "all"} {
+		if !IsValidKillTarget(target) {
+			t.Errorf("IsValidKillTarget(%q) = false, want true", target)
+		}
+	}
+
+	for _, target := range []string{"ALL", "children", "main control"} {
+		if IsValidKillTarget(target) {
+			t.Errorf("IsValidKillTarget(%q) = true, want false", target)
+		}
+	}
+}
+
+// TestUnitType tests deriving the unit type from a unit name
+func TestUnitType(t *testing.T) {
+	testCases := map[string]string{
+		"nginx.service":      "service",
+		"backup.timer":       "timer",
+		"docker.socket":      "socket",
+		"home.mount":         "mount",
+		"multi-user.target":  "target",
+		"getty@tty1.service": "service",
+		"nginx":              "",
+		"config.d.conf":      "",
+		"":                   "",
+	}
+
+	for name, expected := range testCases {
+		if got := UnitType(name); got != expected {
+			t.Errorf("UnitType(%q) = %q, want %q", name, got, expected)
+		}
+	}
+}
+
+// TestGetCalendarExpressions tests extracting OnCalendar expressions from timer properties
+func TestGetCalendarExpressions(t *testing.T) {
+	props := map[string]interface{}{
+		"TimersCalendar": [][]interface{}{
+			{"OnCalendar", "*-*-* 03:00:00", uint64(0)},
+			{"OnCalendar", "Mon *-*-* 12:00:00", uint64(0)},
+		},
+	}
+
+	expressions := getCalendarExpressions(props)
+	if len(expressions) != 2 || expressions[0] != "*-*-* 03:00:00" || expressions[1] != "Mon *-*-* 12:00:00" {
+		t.Errorf("getCalendarExpressions failed: got %v", expressions)
+	}
+
+	if val := getCalendarExpressions(map[string]interface{}{}); len(val) != 0 {
+		t.Errorf("getCalendarExpressions for missing property should return empty array, got %v", val)
+	}
+}
+
+// TestFormatTimestamp tests formatting systemd microsecond timestamps
+func TestFormatTimestamp(t *testing.T) {
+	if val := formatTimestamp(0); val != "" {
+		t.Errorf("formatTimestamp(0) should return empty string, got %s", val)
+	}
+
+	if val := formatTimestamp(math.MaxUint64); val != "" {
+		t.Errorf("formatTimestamp(MaxUint64) should return empty string, got %s", val)
+	}
+
+	usec := uint64(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMicro())
+	parsed, err := time.Parse(time.RFC3339, formatTimestamp(usec))
+	if err != nil || parsed.UnixMicro() != int64(usec) {
+		t.Errorf("formatTimestamp(%d) round trip failed: got %v, err %v", usec, parsed, err)
+	}
+}
+
+// TestIsTemplateUnit tests detecting template unit files
+func TestIsTemplateUnit(t *testing.T) {
+	testCases := map[string]bool{
+		"getty@.service":     true,
+		"getty@tty1.service": false,
+		"sshd.service":       false,
+		"user@.slice":        true,
+	}
+
+	for name, expected := range testCases {
+		if got := isTemplateUnit(name); got != expected {
+			t.Errorf("isTemplateUnit(%q) = %v, want %v", name, got, expected)
+		}
+	}
+}
+
+// TestIsStandaloneUnitFile tests skipping unit files that do not define a unit of their own
+func TestIsStandaloneUnitFile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		state    string
+		expected bool
+	}{
+		{"sshd.service", "disabled", true},
+		{"systemd-networkd-wait-online.service", "generated", true},
+		{"dbus-org.freedesktop.timesync1.service", "alias", false},
+		{"custom.service", "linked", false},
+		{"custom.service", "linked-runtime", false},
+		{"getty@.service", "enabled", false},
+	}
+
+	for _, tc := range testCases {
+		if got := isStandaloneUnitFile(tc.name, tc.state); got != tc.expected {
+			t.Errorf("isStandaloneUnitFile(%q, %q) = %v, want %v", tc.name, tc.state, got, tc.expected)
+		}
+	}
+}
+
+// TestUnitDescriptionCache tests that unit file descriptions are reread only after a change
+func TestUnitDescriptionCache(t *testing.T) {
+	service := &SystemdService{logger: slog.Default()}
+	path := filepath.Join(t.TempDir(), "test.service")
+	if err := os.WriteFile(path, []byte(testServiceTemplate), 0644); err != nil {
+		t.Fatalf("Failed to write unit file: %v", err)
+	}
+	if val := service.unitDescription(path); val != "Sirberus Test Service" {
+		t.Fatalf("unitDescription() = %q, want %q", val, "Sirberus Test Service")
+	}
+
+	// Replace the cached description to detect whether the file is read again
+	modTime := service.descriptions[path].modTime
+	service.descriptions[path] = cachedDescription{modTime: modTime, description: "cached"}
+	if val := service.unitDescription(path); val != "cached" {
+		t.Errorf("unitDescription() of unchanged file = %q, want cached description", val)
+	}
+
+	if err := os.Chtimes(path, time.Time{}, modTime.Add(time.Second)); err != nil {
+		t.Fatalf("Failed to change modification time: %v", err)
+	}
+	if val := service.unitDescription(path); val != "Sirberus Test Service" {
+		t.Errorf("unitDescription() of changed file = %q, want %q", val, "Sirberus Test Service")
+	}
+}
+
+// TestReadUnitDescription tests reading the description from a unit file
+func TestReadUnitDescription(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.service")
+	if err := os.WriteFile(path, []byte(testServiceTemplate), 0644); err != nil {
+		t.Fatalf("Failed to write unit file: %v", err)
+	}
+
+	if val := readUnitDescription(path); val != "Sirberus Test Service" {
+		t.Errorf("readUnitDescription failed: got %q, want %q", val, "Sirberus Test Service")
+	}
+
+	if val := readUnitDescription(filepath.Join(t.TempDir(), "missing.service")); val != "" {
+		t.Errorf("readUnitDescription for missing file should return empty string, got %q", val)
+	}
+}
+
+// TestUnitFileOrigin tests classifying unit file paths by origin
+func TestUnitFileOrigin(t *testing.T) {
+	testCases := map[string]string{
+		"/usr/lib/systemd/system/sshd.service":                "vendor",
+		"/lib/systemd/system/sshd.service":                    "vendor",
+		"/etc/systemd/system/sshd.service":                    "admin",
+		"/etc/systemd/system/sshd.service.d/override.conf":    "admin",
+		"/run/systemd/system/session-1.scope.d/50-Slice.conf": "runtime",
+		"/home/user/.config/systemd/user/sync.service":        "other",
+	}
+
+	for path, expected := range testCases {
+		if got := unitFileOrigin(path); got != expected {
+			t.Errorf("unitFileOrigin(%q) = %q, want %q", path, got, expected)
+		}
+	}
+}
+
+// TestOverridePath tests building drop-in paths and rejecting unsafe names
+func TestOverridePath(t *testing.T) {
+	path, err := overridePath("/etc/systemd/system", "nginx.service", "override")
+	if err != nil || path != "/etc/systemd/system/nginx.service.d/override.conf" {
+		t.Errorf("overridePath failed: got %s, err %v", path, err)
+	}
+
+	path, err = overridePath("/etc/systemd/system", "backup.timer", "10-schedule.conf")
+	if err != nil || path != "/etc/systemd/system/backup.timer.d/10-schedule.conf" {
+		t.Errorf("overridePath failed: got %s, err %v", path, err)
+	}
+
+	invalid := []struct{ unit, override string }{
+		{"nginx", "override"},
+		{"../nginx.service", "override"},
+		{"nginx.service", "../../passwd"},
+		{"nginx.service", ".hidden"},
+		{"nginx.service", "a/b"},
+		{"nginx.service", ""},
+	}
+	for _, tc := range invalid {
+		if _, err := overridePath("/etc/systemd/system", tc.unit, tc.override); !errors.Is(err, ErrInvalidUnitFileName) {
+			t.Errorf("overridePath(%q, %q) should fail with ErrInvalidUnitFileName, got %v", tc.unit, tc.override, err)
+		}
+	}
+}
+
+// TestStageDropIn tests staging a drop-in next to the unit directory and moving it into place
+func TestStageDropIn(t *testing.T) {
+	unitDir := t.TempDir()
+	target := filepath.Join(unitDir, "test.service.d", "override.conf")
+
+	staged, err := stageDropIn(target, []byte("[Service]\nNice=5\n"))
+	if err != nil {
+		t.Fatalf("stageDropIn failed: %v", err)
+	}
+	defer staged.remove()
+
+	if filepath.Dir(staged.dir) != unitDir || !strings.HasPrefix(filepath.Base(staged.dir), ".") {
+		t.Errorf("Staging directory %s should be hidden inside %s", staged.dir, unitDir)
+	}
+	if staged.unitPath() != staged.dir+":" {
+		t.Errorf("unitPath() = %s, want the staging directory before the default search path", staged.unitPath())
+	}
+	if content, err := os.ReadFile(filepath.Join(staged.dir, "test.service.d", "override.conf")); err != nil || string(content) != "[Service]\nNice=5\n" {
+		t.Errorf("Staged drop-in = %q, err %v", content, err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("Drop-in should not be installed before commit, got %v", err)
+	}
+
+	if err := staged.commit(); err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	if content, err := os.ReadFile(target); err != nil || string(content) != "[Service]\nNice=5\n" {
+		t.Errorf("Committed drop-in = %q, err %v", content, err)
+	}
+
+	staged.remove()
+	if _, err := os.Stat(staged.dir); !os.IsNotExist(err) {
+		t.Errorf("Staging directory should have been removed, got %v", err)
+	}
+}
+
+// TestRenderServiceUnit tests rendering unit specs to unit files
+func TestRenderServiceUnit(t *testing.T) {
+	spec := types.SystemdUnitSpec{
+		Name:             "myapp.service",
+		Description:      "My App",
+		ExecStart:        "/usr/bin/myapp --port 8080",
+		User:             "myapp",
+		WorkingDirectory: "/srv/myapp",
+		Environment:      map[string]string{"MODE": "production", "GREETING": `say "hi" 100%`},
+		Restart:          "on-failure",
+	}
+
+	content, err := renderServiceUnit(spec)
+	if err != nil {
+		t.Fatalf("renderServiceUnit failed: %v", err)
+	}
+
+	expected := `# Managed by Sirberus
+[Unit]
+Description=My App
+
+[Service]
+ExecStart=/usr/bin/myapp --port 8080
+User=myapp
+WorkingDirectory=/srv/myapp
+Environment="GREETING=say \"hi\" 100%%"
+Environment="MODE=production"
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`
+	if content != expected {
+		t.Errorf("renderServiceUnit mismatch:\ngot:\n%s\nwant:\n%s", content, expected)
+	}
+
+	// Services with a timer are installed through the timer
+	spec.Timer = &types.SystemdTimerSpec{OnCalendar: []string{"daily"}, Persistent: true}
+	content, err = renderServiceUnit(spec)
+	if err != nil {
+		t.Fatalf("renderServiceUnit with timer failed: %v", err)
+	}
+	if strings.Contains(content, "[Install]") {
+		t.Errorf("Service with timer should not have an [Install] section:\n%s", content)
+	}
+
+	timer := renderTimerUnit(spec)
+	for _, line := range []string{"OnCalendar=daily", "Persistent=true", "Unit=myapp.service", "WantedBy=timers.target"} {
+		if !strings.Contains(timer, line+"\n") {
+			t.Errorf("Timer unit is missing %q:\n%s", line, timer)
+		}
+	}
+}
+
+// TestRenderServiceUnitInvalid tests rejecting unit specs that are incomplete or would inject settings
+func TestRenderServiceUnitInvalid(t *testing.T) {
+	valid := types.SystemdUnitSpec{Name: "myapp.service", ExecStart: "/usr/bin/myapp"}
+
+	testCases := map[string]func(spec *types.SystemdUnitSpec){
+		"Missing ExecStart":    func(spec *types.SystemdUnitSpec) { spec.ExecStart = "" },
+		"Invalid name":         func(spec *types.SystemdUnitSpec) { spec.Name = "../myapp.service" },
+		"Invalid restart":      func(spec *types.SystemdUnitSpec) { spec.Restart = "sometimes" },
+		"Injected setting":     func(spec *types.SystemdUnitSpec) { spec.Description = "x\nExecStartPre=/bin/evil" },
+		"Injected environment": func(spec *types.SystemdUnitSpec) { spec.Environment = map[string]string{"A": "1\n[Service]"} },
+		"Invalid env name":     func(spec *types.SystemdUnitSpec) { spec.Environment = map[string]string{"A B": "1"} },
+		"Empty timer":          func(spec *types.SystemdUnitSpec) { spec.Timer = &types.SystemdTimerSpec{} },
+	}
+
+	for name, modify := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spec := valid
+			modify(&spec)
+			if _, err := renderServiceUnit(spec); !errors.Is(err, ErrInvalidUnitSpec) {
+				t.Errorf("renderServiceUnit should fail with ErrInvalidUnitSpec, got %v", err)
+			}
+		})
+	}
+}
+
+// TestParseByteSize tests parsing memory sizes with binary suffixes
+func TestParseByteSize(t *testing.T) {
+	testCases := map[string]uint64{
+		"1024": 1024,
+		"512K": 512 << 10,
+		"512M": 512 << 20,
+		"2g":   2 << 30,
+		"1T":   1 << 40,
+	}
+
+	for size, expected := range testCases {
+		if got, err := parseByteSize(size); err != nil || got != expected {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", size, got, err, expected)
+		}
+	}
+
+	for _, size := range []string{"", "M", "12X", "-1", "1.5G", "99999999999T", "18446744073709551615K"} {
+		if _, err := parseByteSize(size); err == nil {
+			t.Errorf("parseByteSize(%q) should fail", size)
+		}
+	}
+}
+
+// TestTransientResourceProperties tests converting run request limits to unit properties
+func TestTransientResourceProperties(t *testing.T) {
+	properties, err := transientResourceProperties(types.SystemdRunRequest{
+		MemoryMax:     "256M",
+		CPUQuota:      "50%",
+		RuntimeMaxSec: 60,
+	})
+	if err != nil {
+		t.Fatalf("transientResourceProperties failed: %v", err)
+	}
+
+	expected := map[string]uint64{
+		"MemoryMax":          256 << 20,
+		"CPUQuotaPerSecUSec": 500000,
+		"RuntimeMaxUSec":     60000000,
+	}
+	if len(properties) != len(expected) {
+		t.Fatalf("Expected %d properties, got %d", len(expected), len(properties))
+	}
+	for _, p := range properties {
+		if got, ok := p.Value.Value().(uint64); !ok || got != expected[p.Name] {
+			t.Errorf("Property %s = %v, want %d", p.Name, p.Value.Value(), expected[p.Name])
+		}
+	}
+
+	if _, err := transientResourceProperties(types.SystemdRunRequest{CPUQuota: "lots"}); err == nil {
+		t.Error("transientResourceProperties should fail for an invalid CPU quota")
+	}
+	if _, err := transientResourceProperties(types.SystemdRunRequest{RuntimeMaxSec: math.MaxUint64 / 1000}); !errors.Is(err, ErrInvalidProperty) {
+		t.Errorf("transientResourceProperties should fail with ErrInvalidProperty for an overflowing runtime, got %v", err)
+	}
+}
+
+// TestTransientUnitName tests generating unique transient unit names
+func TestTransientUnitName(t *testing.T) {
+	service, err := transientUnitName(false)
+	if err != nil || !strings.HasPrefix(service, transientUnitPrefix) || UnitType(service) != "service" {
+		t.Errorf("transientUnitName(false) = %s, %v", service, err)
+	}
+
+	scope, err := transientUnitName(true)
+	if err != nil || UnitType(scope) != "scope" {
+		t.Errorf("transientUnitName(true) = %s, %v", scope, err)
+	}
+
+	if other, _ := transientUnitName(false); other == service {
+		t.Errorf("transientUnitName should generate unique names, got %s twice", service)
+	}
+}
+
+// TestResourceProperty tests converting resource settings to D-Bus properties
+func TestResourceProperty(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		property string
+		expected interface{}
+	}{
+		{"CPUQuota", "50%", "CPUQuotaPerSecUSec", uint64(500000)},
+		{"CPUQuota", "infinity", "CPUQuotaPerSecUSec", uint64(math.MaxUint64)},
+		{"MemoryMax", "1G", "MemoryMax", uint64(1 << 30)},
+		{"MemoryHigh", "infinity", "MemoryHigh", uint64(math.MaxUint64)},
+		{"TasksMax", "512", "TasksMax", uint64(512)},
+		{"IOWeight", "200", "IOWeight", uint64(200)},
+		{"AllowedCPUs", "0-2,9", "AllowedCPUs", []byte{0x07, 0x02}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name+"="+tc.value, func(t *testing.T) {
+			property, err := resourceProperty(tc.name, tc.value)
+			if err != nil {
+				t.Fatalf("resourceProperty failed: %v", err)
+			}
+			if property.Name != tc.property || fmt.Sprint(property.Value.Value()) != fmt.Sprint(tc.expected) {
+				t.Errorf("resourceProperty = %s=%v, want %s=%v", property.Name, property.Value.Value(), tc.property, tc.expected)
+			}
+		})
+	}
+
+	invalid := []struct{ name, value string }{
+		{"CPUQuota", "0%"},
+		{"CPUQuota", "1844674407370956%"},
+		{"MemoryMax", "lots"},
+		{"IOWeight", "0"},
+		{"IOWeight", "10001"},
+		{"AllowedCPUs", "3-1"},
+		{"ExecStart", "/bin/sh"},
+	}
+	for _, tc := range invalid {
+		if _, err := resourceProperty(tc.name, tc.value); !errors.Is(err, ErrInvalidProperty) {
+			t.Errorf("resourceProperty(%s, %s) should fail with ErrInvalidProperty, got %v", tc.name, tc.value, err)
+		}
+	}
+}
+
+// TestGetResourceLimits tests formatting the current resource limits of a unit
+func TestGetResourceLimits(t *testing.T) {
+	props := map[string]interface{}{
+		"CPUQuotaPerSecUSec": uint64(1500000),
+		"MemoryMax":          uint64(math.MaxUint64),
+		"MemoryHigh":         uint64(536870912),
+		"TasksMax":           uint64(4915),
+		"IOWeight":           uint64(math.MaxUint64),
+		"AllowedCPUs":        []byte{0x0f, 0x01},
+	}
+
+	expected := types.SystemdResourceLimits{
+		CPUQuota:    "150%",
+		MemoryMax:   "infinity",
+		MemoryHigh:  "536870912",
+		TasksMax:    "4915",
+		IOWeight:    "",
+		AllowedCPUs: "0-3,8",
+	}
+	if limits := getResourceLimits(props); limits != expected {
+		t.Errorf("getResourceLimits = %+v, want %+v", limits, expected)
+	}
+}
+
+// TestWalkDependencies tests that truncated dependency graphs have no edges to missing nodes
+func TestWalkDependencies(t *testing.T) {
+	wants := make([]string, 0, maxDependencyNodes+10)
+	for i := range maxDependencyNodes + 10 {
+		wants = append(wants, fmt.Sprintf("dep%d.service", i))
+	}
+	unitProperties := func(unit string) (map[string]interface{}, error) {
+		props := map[string]interface{}{"LoadState": "loaded", "ActiveState": "active", "SubState": "running"}
+		switch unit {
+		case "root.target":
+			props["Wants"] = wants
+		case "dep0.service":
+			// A visited unit and one that no longer fits
+			props["Wants"] = []string{"root.target", wants[len(wants)-1]}
+		}
+		return props, nil
+	}
+
+	graph := &types.SystemdDependencyGraph{Root: "root.target", Depth: 2}
+	if err := walkDependencies(graph, []string{"Wants"}, unitProperties); err != nil {
+		t.Fatalf("walkDependencies failed: %v", err)
+	}
+
+	if !graph.Truncated || len(graph.Nodes) != maxDependencyNodes {
+		t.Errorf("Expected truncated graph with %d nodes, got truncated=%v with %d", maxDependencyNodes, graph.Truncated, len(graph.Nodes))
+	}
+	nodes := map[string]bool{}
+	for _, node := range graph.Nodes {
+		nodes[node.Name] = true
+	}
+	for _, edge := range graph.Edges {
+		if !nodes[edge.From] || !nodes[edge.To] {
+			t.Errorf("Edge %s -> %s points to a unit that is not a node", edge.From, edge.To)
+		}
+	}
+	// The root wants every node but itself, and dep0 links back to the root
+	if len(graph.Edges) != maxDependencyNodes {
+		t.Errorf("Expected %d edges, got %d", maxDependencyNodes, len(graph.Edges))
+	}
+}
+
+// TestIsValidDependencyDirection tests dependency traversal direction validation
+func TestIsValidDependencyDirection(t *testing.T) {
+	for _, direction := range []string{"forward", "reverse"} {
+		if !IsValidDependencyDirection(direction) {
+			t.Errorf("IsValidDependencyDirection(%q) = false, want true", direction)
+		}
+	}
+
+	for _, direction := range []string{"", "both", "Forward"} {
+		if IsValidDependencyDirection(direction) {
+			t.Errorf("IsValidDependencyDirection(%q) = true, want false", direction)
+		}
+	}
+}
+
+// TestApplyUnitStateUpdate tests merging PropertiesChanged signals into known unit states
+func TestApplyUnitStateUpdate(t *testing.T) {
+	states := map[string]types.SystemdUnitEvent{
+		"nginx.service": {Unit: "nginx.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+	}
+
+	event, changed := applyUnitStateUpdate(states, "nginx.service", map[string]godbus.Variant{
+		"ActiveState": godbus.MakeVariant("deactivating"),
+		"SubState":    godbus.MakeVariant("stop-sigterm"),
+	})
+	if !changed {
+		t.Fatal("expected state change to be reported")
+	}
+	expected := types.SystemdUnitEvent{Unit: "nginx.service", LoadState: "loaded", ActiveState: "deactivating", SubState: "stop-sigterm"}
+	if event != expected {
+		t.Errorf("applyUnitStateUpdate() = %+v, want %+v", event, expected)
+	}
+
+	if _, changed := applyUnitStateUpdate(states, "nginx.service", map[string]godbus.Variant{
+		"ActiveState":          godbus.MakeVariant("deactivating"),
+		"ActiveEnterTimestamp": godbus.MakeVariant(uint64(1)),
+	}); changed {
+		t.Error("expected unchanged state not to be reported")
+	}
+
+	if _, changed := applyUnitStateUpdate(states, "other.service", map[string]godbus.Variant{
+		"Description": godbus.MakeVariant("Other"),
+	}); changed {
+		t.Error("expected unknown unit without state properties not to be reported")
+	}
+
+	if event, changed := applyUnitStateUpdate(states, "new.service", map[string]godbus.Variant{
+		"ActiveState": godbus.MakeVariant("activating"),
+	}); !changed || event.ActiveState != "activating" {
+		t.Errorf("expected new unit to be reported, got %+v (changed=%v)", event, changed)
+	}
+}
+
+// TestJobTracking tests recording results of queued jobs and waiting for them
+func TestJobTracking(t *testing.T) {
+	s := &SystemdService{
+		logger: slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		jobs:   make(map[uint32]*trackedJob),
+	}
+	s.jobs[42] = &trackedJob{
+		job:  types.SystemdJob{ID: 42, Unit: "nginx.service", Type: "stop", State: jobStateWaiting},
+		done: make(chan struct{}),
+	}
+
+	if _, err := s.waitForJobResult(42, 10*time.Millisecond); err == nil {
+		t.Error("expected timeout while the job is still waiting")
+	}
+
+	go s.finishJob(42, "canceled")
+	result, err := s.waitForJobResult(42, time.Second)
+	if err != nil {
+		t.Fatalf("waitForJobResult() failed: %v", err)
+	}
+	if result != "canceled" {
+		t.Errorf("waitForJobResult() = %q, want %q", result, "canceled")
+	}
+	if state := s.jobs[42].job.State; state != jobStateFinished {
+		t.Errorf("job state = %q, want %q", state, jobStateFinished)
+	}
+
+	if _, err := s.waitForJobResult(7, time.Second); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("waitForJobResult() for unknown job = %v, want ErrJobNotFound", err)
+	}
+
+	s.jobs[42].finishedAt = time.Now().Add(-2 * jobRetention)
+	s.pruneJobs()
+	if _, ok := s.jobs[42]; ok {
+		t.Error("expected expired job to be pruned")
+	}
+}
+
+// TestParseScope tests parsing the manager scope selector
+func TestParseScope(t *testing.T) {
+	ownUID := uint32(os.Getuid())
+	testCases := []struct {
+		scope    string
+		uid      string
+		expected Scope
+		valid    bool
+	}{
+		{"", "", Scope{}, true},
+		{"system", "", Scope{}, true},
+		{"user", "", Scope{User: true, UID: ownUID}, true},
+		{"user", "1000", Scope{User: true, UID: 1000}, true},
+		{"system", "1000", Scope{}, false},
+		{"user", "-1", Scope{}, false},
+		{"user", "alice", Scope{}, false},
+		{"session", "", Scope{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scope+"_"+tc.uid, func(t *testing.T) {
+			got, err := ParseScope(tc.scope, tc.uid)
+			if tc.valid != (err == nil) {
+				t.Fatalf("ParseScope(%q, %q) error = %v, want valid %v", tc.scope, tc.uid, err, tc.valid)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidScope) {
+				t.Errorf("ParseScope(%q, %q) error = %v, want ErrInvalidScope", tc.scope, tc.uid, err)
+			}
+			if got != tc.expected {
+				t.Errorf("ParseScope(%q, %q) = %+v, want %+v", tc.scope, tc.uid, got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestJournalUnitArgs tests selecting unit messages for each manager scope
+func TestJournalUnitArgs(t *testing.T) {
+	ownUID := uint32(os.Getuid())
+	testCases := []struct {
+		scope    Scope
+		expected []string
+	}{
+		{Scope{}, []string{"--unit", "app.service"}},
+		{Scope{User: true, UID: ownUID}, []string{"--user-unit", "app.service"}},
+		{Scope{User: true, UID: ownUID + 1}, []string{"_SYSTEMD_USER_UNIT=app.service", fmt.Sprintf("_UID=%d", ownUID+1)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scope.String(), func(t *testing.T) {
+			s := &SystemdService{scope: tc.scope}
+			got := s.journalUnitArgs("app.service")
+			if strings.Join(got, " ") != strings.Join(tc.expected, " ") {
+				t.Errorf("journalUnitArgs() = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestForScope tests that services are cached per manager scope
+func TestForScope(t *testing.T) {
+	s := &SystemdService{logger: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	if s.ForScope(Scope{}) != s {
+		t.Error("ForScope() with the own scope should return the service itself")
+	}
+
+	user := s.ForScope(Scope{User: true, UID: 1000})
+	if user == s || user.scope != (Scope{User: true, UID: 1000}) {
+		t.Errorf("ForScope() returned service with scope %+v", user.scope)
+	}
+	if s.ForScope(Scope{User: true, UID: 1000}) != user {
+		t.Error("ForScope() should return the cached service for a known scope")
+	}
+	if userBusPath(1000) != "/run/user/1000/bus" {
+		t.Errorf("userBusPath(1000) = %q", userBusPath(1000))
+	}
+}
+
+func TestBootAnalysis(t *testing.T) {
+	boot := bootTimes{userspace: 1_000_000, finish: 9_000_000}
+	timings := map[string]unitTiming{
+		"graphical.target":  {activating: 8_500_000, activated: 8_500_000, after: []string{"multi-user.target", "display-manager.service"}},
+		"multi-user.target": {activating: 8_400_000, activated: 8_400_000, after: []string{"nginx.service", "sshd.service"}},
+		"nginx.service":     {activating: 2_000_000, activated: 8_000_000, execMainStart: 7_500_000, after: []string{"network.target"}},
+		"sshd.service":      {activating: 2_000_000, activated: 3_000_000, after: []string{"network.target"}},
+		"network.target":    {activating: 1_900_000, activated: 1_900_000},
+		// Restarted after the boot finished, so not part of the chain
+		"display-manager.service": {activating: 20_000_000, activated: 20_500_000},
+	}
+
+	blame := buildBlame(timings, boot)
+	var names []string
+	for _, timing := range blame {
+		names = append(names, timing.Name)
+	}
+	if got := strings.Join(names, " "); got != "nginx.service sshd.service display-manager.service" {
+		t.Errorf("buildBlame() order = %s", got)
+	}
+	if blame[0].ActivationTime != 6000 || blame[0].ExecMainStartTime != 5500 || blame[0].ActivatedAt != 7000 {
+		t.Errorf("buildBlame()[0] = %+v, want 6000ms activation, 5500ms to main start, active at 7000ms", blame[0])
+	}
+
+	var chain []string
+	for node := buildCriticalChain("graphical.target", timings, boot, map[string]bool{}); ; node = node.Children[0] {
+		chain = append(chain, node.Name)
+		if len(node.Children) != 1 {
+			if len(node.Children) > 1 {
+				t.Fatalf("%s has %d children, want 1", node.Name, len(node.Children))
+			}
+			break
+		}
+	}
+	if got := strings.Join(chain, " "); got != "graphical.target multi-user.target nginx.service network.target" {
+		t.Errorf("buildCriticalChain() = %s", got)
+	}
+}
+
+func TestAssessSecurity(t *testing.T) {
+	unhardened := map[string]interface{}{
+		"CapabilityBoundingSet": ^uint64(0),
+		"RestrictNamespaces":    ^uint64(0),
+	}
+	if got := assessSecurity("unhardened.service", unhardened); got.Exposure != 10 || got.Rating != "DANGEROUS" {
+		t.Errorf("assessSecurity(unhardened) = %.1f %s, want 10.0 DANGEROUS", got.Exposure, got.Rating)
+	}
+
+	hardened := map[string]interface{}{
+		"DynamicUser":             true,
+		"ProtectSystem":           "strict",
+		"ProtectHome":             "yes",
+		"ProtectProc":             "noaccess",
+		"SystemCallArchitectures": []string{"native"},
+		"SystemCallFilter":        []interface{}{true, []string{"read", "write"}},
+		"RestrictAddressFamilies": []interface{}{true, []string{}},
+		"CapabilityBoundingSet":   uint64(0),
+		"RestrictNamespaces":      uint64(0),
+	}
+	for _, check := range securityChecks {
+		if strings.HasSuffix(check.name, "=") && check.maxBadness == 1 {
+			hardened[strings.TrimSuffix(check.name, "=")] = true
+		}
+	}
+	got := assessSecurity("hardened.service", hardened)
+	if got.Exposure != 0 || got.Rating != "PERFECT" {
+		t.Errorf("assessSecurity(hardened) = %.1f %s, want 0.0 PERFECT", got.Exposure, got.Rating)
+	}
+	for _, check := range got.Checks {
+		if !check.Passed {
+			t.Errorf("assessSecurity(hardened) check %s failed: %s", check.Name, check.Description)
+		}
+	}
+
+	// Only local sockets and the network capabilities of a typical web server
+	partial := map[string]interface{}{
+		"User":                    "www-data",
+		"NoNewPrivileges":         true,
+		"ProtectSystem":           "full",
+		"RestrictAddressFamilies": []interface{}{true, []string{"AF_UNIX"}},
+		"CapabilityBoundingSet":   uint64(1<<capNetBindService | 1<<capSetuid),
+		"RestrictNamespaces":      ^uint64(0),
+	}
+	got = assessSecurity("nginx.service", partial)
+	if got.Exposure <= 0 || got.Exposure >= 10 {
+		t.Errorf("assessSecurity(partial) exposure = %.1f, want between 0 and 10", got.Exposure)
+	}
+	passed := map[string]bool{}
+	for _, check := range got.Checks {
+		passed[check.Name] = check.Passed
+	}
+	for name, want := range map[string]bool{
+		"User=/DynamicUser=":                                          true,
+		"NoNewPrivileges=":                                            true,
+		"ProtectSystem=":                                              false,
+		"RestrictAddressFamilies=~AF_(INET|INET6)":                    true,
+		"RestrictAddressFamilies=~AF_UNIX":                            false,
+		"RestrictAddressFamilies=~…":                                  true,
+		"CapabilityBoundingSet=~CAP_SYS_ADMIN":                        true,
+		"CapabilityBoundingSet=~CAP_SET(UID|GID|PCAP)":                false,
+		"CapabilityBoundingSet=~CAP_NET_(BIND_SERVICE|BROADCAST|RAW)": false,
+		"RestrictNamespaces=~user":                                    false,
+	} {
+		if passed[name] != want {
+			t.Errorf("assessSecurity(partial) check %s passed = %v, want %v", name, passed[name], want)
+		}
+	}
+	for i := 1; i < len(got.Checks); i++ {
+		a, b := got.Checks[i-1], got.Checks[i]
+		if a.Exposure*float64(a.Weight) < b.Exposure*float64(b.Weight) {
+			t.Errorf("assessSecurity(partial) checks not ordered by exposure: %s before %s", a.Name, b.Name)
+		}
+	}
+}
+
+func TestUnitFailureInfo(t *testing.T) {
+	failed := unitFailureInfo(map[string]interface{}{
+		"Result":                 "exit-code",
+		"ExecMainCode":           int32(1),
+		"ExecMainStatus":         int32(203),
+		"NRestarts":              uint32(5),
+		"ActiveExitTimestamp":    uint64(1_700_000_000_000_000),
+		"InactiveEnterTimestamp": uint64(1_700_000_060_000_000),
+	})
+	want := types.SystemdFailureInfo{
+		Result:         "exit-code",
+		ExecMainCode:   "exited",
+		ExecMainStatus: 203,
+		NRestarts:      5,
+		FailedAt:       formatTimestamp(1_700_000_060_000_000),
+	}
+	if !reflect.DeepEqual(failed, want) {
+		t.Errorf("unitFailureInfo(failed) = %+v, want %+v", failed, want)
+	}
+
+	// Restarting services leave the active state without becoming inactive
+	restarting := unitFailureInfo(map[string]interface{}{
+		"Result":                 "signal",
+		"ExecMainCode":           int32(2),
+		"ExecMainStatus":         int32(9),
+		"ActiveExitTimestamp":    uint64(1_700_000_120_000_000),
+		"InactiveEnterTimestamp": uint64(1_700_000_060_000_000),
+	})
+	if restarting.ExecMainCode != "killed" || restarting.FailedAt != formatTimestamp(1_700_000_120_000_000) {
+		t.Errorf("unitFailureInfo(restarting) = %+v, want killed at the active exit", restarting)
+	}
+
+	succeeded := unitFailureInfo(map[string]interface{}{
+		"Result":                 "success",
+		"InactiveEnterTimestamp": uint64(1_700_000_060_000_000),
+	})
+	if succeeded.FailedAt != "" {
+		t.Errorf("unitFailureInfo(succeeded).FailedAt = %q, want empty", succeeded.FailedAt)
+	}
+
+	args := strings.Join(journalInvocationArgs("0123abcd"), " ")
+	if args != "_SYSTEMD_INVOCATION_ID=0123abcd + INVOCATION_ID=0123abcd + USER_INVOCATION_ID=0123abcd" {
+		t.Errorf("journalInvocationArgs() = %s", args)
+	}
+}
+
+func TestConvertUnitFileChanges(t *testing.T) {
+	changes := convertUnitFileChanges([]dbus.EnableUnitFileChange{
+		{Type: "symlink", Filename: "/etc/systemd/system/multi-user.target.wants/nginx.service", Destination: "/usr/lib/systemd/system/nginx.service"},
+	})
+	want := []types.SystemdUnitFileChange{
+		{Type: "symlink", Filename: "/etc/systemd/system/multi-user.target.wants/nginx.service", Destination: "/usr/lib/systemd/system/nginx.service"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("convertUnitFileChanges() = %+v, want %+v", changes, want)
+	}
+	if changes := convertUnitFileChanges([]dbus.UnmaskUnitFileChange(nil)); changes == nil || len(changes) != 0 {
+		t.Errorf("convertUnitFileChanges(nil) = %#v, want empty slice", changes)
+	}
+}
+
+// TestStopTimeout tests bounding the wait for stop jobs by the unit's stop timeout
+func TestStopTimeout(t *testing.T) {
+	testCases := map[uint64]time.Duration{
+		0:                 stopJobTimeoutMargin,
+		90_000_000:        90*time.Second + stopJobTimeoutMargin,
+		math.MaxUint64:    jobWaitTimeout,
+		3_600_000_000_000: jobWaitTimeout,
+	}
+
+	for usec, expected := range testCases {
+		if got := stopTimeout(usec); got != expected {
+			t.Errorf("stopTimeout(%d) = %s, want %s", usec, got, expected)
+		}
+	}
+}
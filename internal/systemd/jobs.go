@@ -0,0 +1,274 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrJobNotFound is returned for jobs that are neither queued nor tracked
+var ErrJobNotFound = errors.New("job not found")
+
+// trackedJob is a job queued by Sirberus whose result arrives with the JobRemoved signal
+type trackedJob struct {
+	job        types.SystemdJob
+	done       chan struct{}
+	finishedAt time.Time
+}
+
+// trackJob records a queued job and waits in the background for its result on conn,
+// closing the connection once the job has finished or jobWaitTimeout has passed
+func (s *SystemdService) trackJob(ctx context.Context, cancel context.CancelFunc, conn *dbus.Conn, job types.SystemdJob, resultCh <-chan string) {
+	s.jobsMu.Lock()
+	s.pruneJobs()
+	s.jobs[job.ID] = &trackedJob{job: job, done: make(chan struct{})}
+	s.jobsMu.Unlock()
+
+	go func() {
+		defer cancel()
+		defer conn.Close()
+
+		select {
+		case result := <-resultCh:
+			s.finishJob(job.ID, result)
+		case <-ctx.Done():
+			s.logger.Warn("stopped waiting for job result",
+				"job", job.ID,
+				"unit", job.Unit)
+			s.jobsMu.Lock()
+			delete(s.jobs, job.ID)
+			s.jobsMu.Unlock()
+		}
+	}()
+}
+
+// finishJob stores the result of a tracked job and wakes up everyone waiting for it
+func (s *SystemdService) finishJob(id uint32, result string) {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	tracked, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	tracked.job.State = jobStateFinished
+	tracked.job.Result = result
+	tracked.finishedAt = time.Now()
+	close(tracked.done)
+
+	s.logger.Info("job finished",
+		"job", id,
+		"unit", tracked.job.Unit,
+		"type", tracked.job.Type,
+		"result", result)
+}
+
+// pruneJobs forgets finished jobs older than jobRetention. The caller must hold jobsMu.
+func (s *SystemdService) pruneJobs() {
+	for id, tracked := range s.jobs {
+		if !tracked.finishedAt.IsZero() && time.Since(tracked.finishedAt) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// waitForJobResult blocks until a tracked job has finished and returns its result
+func (s *SystemdService) waitForJobResult(id uint32, timeout time.Duration) (string, error) {
+	s.jobsMu.Lock()
+	tracked, ok := s.jobs[id]
+	s.jobsMu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %d", ErrJobNotFound, id)
+	}
+
+	select {
+	case <-tracked.done:
+	case <-time.After(timeout):
+		return "", fmt.Errorf("timed out waiting for job %d", id)
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	return tracked.job.Result, nil
+}
+
+// ListJobs returns the jobs currently queued in systemd together with the
+// recently finished jobs queued by Sirberus
+func (s *SystemdService) ListJobs() (*types.SystemdJobList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	queued, err := conn.ListJobsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make(map[uint32]types.SystemdJob, len(queued))
+	for _, job := range queued {
+		jobs[job.Id] = types.SystemdJob{
+			ID:    job.Id,
+			Unit:  job.Unit,
+			Type:  job.JobType,
+			State: job.Status,
+		}
+	}
+
+	s.jobsMu.Lock()
+	for id, tracked := range s.jobs {
+		if _, ok := jobs[id]; !ok && tracked.job.State == jobStateFinished {
+			jobs[id] = tracked.job
+		}
+	}
+	s.jobsMu.Unlock()
+
+	list := &types.SystemdJobList{Jobs: make([]types.SystemdJob, 0, len(jobs))}
+	for _, job := range jobs {
+		list.Jobs = append(list.Jobs, job)
+	}
+	sort.Slice(list.Jobs, func(i, j int) bool {
+		return list.Jobs[i].ID < list.Jobs[j].ID
+	})
+	list.Count = len(list.Jobs)
+
+	return list, nil
+}
+
+// GetJob returns the current state of a job
+func (s *SystemdService) GetJob(id uint32) (*types.SystemdJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return s.lookupJob(ctx, conn, id)
+}
+
+// lookupJob finds a job in the systemd queue, falling back to the jobs tracked by Sirberus.
+// Jobs that are neither queued nor tracked have finished without a known result.
+func (s *SystemdService) lookupJob(ctx context.Context, conn *dbus.Conn, id uint32) (*types.SystemdJob, error) {
+	s.jobsMu.Lock()
+	tracked, ok := s.jobs[id]
+	var job types.SystemdJob
+	if ok {
+		job = tracked.job
+	}
+	s.jobsMu.Unlock()
+
+	if ok && job.State == jobStateFinished {
+		return &job, nil
+	}
+
+	queued, err := conn.ListJobsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, q := range queued {
+		if q.Id == id {
+			return &types.SystemdJob{
+				ID:    q.Id,
+				Unit:  q.Unit,
+				Type:  q.JobType,
+				State: q.Status,
+			}, nil
+		}
+	}
+
+	if ok {
+		// The job has left the queue but its JobRemoved signal has not been handled yet
+		return &job, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrJobNotFound, id)
+}
+
+// WatchJob sends the state of a job whenever it changes until the job has finished
+func (s *SystemdService) WatchJob(ctx context.Context, id uint32) (<-chan types.SystemdJob, <-chan error) {
+	jobCh := make(chan types.SystemdJob)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(jobCh)
+		defer close(errCh)
+
+		conn, err := s.newConnection(ctx)
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer conn.Close()
+
+		ticker := time.NewTicker(jobPollInterval)
+		defer ticker.Stop()
+
+		var last types.SystemdJob
+		for {
+			job, err := s.lookupJob(ctx, conn, id)
+			if errors.Is(err, ErrJobNotFound) && last.ID != 0 {
+				// The job left the queue without a tracked result, so it finished
+				finished := last
+				finished.State = jobStateFinished
+				job, err = &finished, nil
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			if *job != last {
+				select {
+				case jobCh <- *job:
+				case <-ctx.Done():
+					return
+				}
+				last = *job
+			}
+			if job.State == jobStateFinished {
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return jobCh, errCh
+}
+
+// CancelJob cancels a queued or running job. go-systemd has no binding for
+// CancelJob, so the manager is called directly.
+func (s *SystemdService) CancelJob(id uint32) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.dialBus(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to systemd: %w", err)
+	}
+	defer conn.Close()
+
+	manager := conn.Object("org.freedesktop.systemd1", godbus.ObjectPath("/org/freedesktop/systemd1"))
+	if err := manager.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.CancelJob", 0, id).Err; err != nil {
+		return fmt.Errorf("failed to cancel job %d: %w", id, err)
+	}
+
+	return nil
+}
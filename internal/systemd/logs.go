@@ -2,205 +2,485 @@ package systemd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
 )
 
-type LogEntry struct {
-	// Timestamp of the log entry as a string
-	Timestamp string
-	// Message content
-	Message string
-	// Unit name that generated the log
-	UnitName string
+// errJournalStart is returned when journalctl could not be started at all
+var errJournalStart = errors.New("failed to start journalctl")
+
+// ErrInvalidCursor is returned for log history cursors that were not issued by Sirberus
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// journalQuery describes the journal entries read by journalctl
+type journalQuery struct {
+	// matches selects the entries, e.g. the unit arguments of a scope
+	matches []string
+	// lines is the number of entries to show from the end of the journal, 0 for all
+	lines int
+	// follow keeps streaming new entries as they are written
+	follow bool
+	// afterCursor starts reading after the entry with this cursor
+	afterCursor string
+	// cursor starts reading at the entry with this cursor
+	cursor string
+	// reverse reads from the newest entry backwards
+	reverse bool
+	// filter narrows down the entries; grep is applied to the parsed messages
+	filter types.LogFilter
+	// output is the journalctl output mode, json if empty
+	output string
 }
 
-func (e LogEntry) String() string {
-	// Pass the timestamp directly to the frontend
-	return fmt.Sprintf("%s: %s", e.Timestamp, e.Message)
+// args returns the journalctl arguments for the query
+func (q journalQuery) args() []string {
+	output := q.output
+	if output == "" {
+		output = "json"
+	}
+	args := append([]string{"--output", output}, q.matches...)
+	if q.afterCursor != "" {
+		args = append(args, "--after-cursor", q.afterCursor)
+	} else if q.cursor != "" {
+		args = append(args, "--cursor", q.cursor)
+	} else if q.lines > 0 {
+		args = append(args, "--lines", strconv.Itoa(q.lines))
+	}
+	if q.follow {
+		args = append(args, "--follow")
+	}
+	if q.reverse {
+		args = append(args, "--reverse")
+	}
+	if !q.filter.Since.IsZero() && !q.hasCursor() {
+		args = append(args, "--since", fmt.Sprintf("@%d", q.filter.Since.Unix()))
+	}
+	if !q.filter.Until.IsZero() {
+		args = append(args, "--until", fmt.Sprintf("@%d", q.filter.Until.Unix()))
+	}
+	if q.filter.Priority != "" {
+		args = append(args, "--priority", q.filter.Priority)
+	}
+	if q.filter.Boot != "" {
+		args = append(args, "--boot", q.filter.Boot)
+	}
+	return args
 }
 
-// StreamServiceLogs retrieves the last numLines log entries for the specified unit
-// and then continues streaming new logs as they arrive
-// The follow parameter is ignored - we always stream real-time updates
-func (s *SystemdService) StreamServiceLogs(ctx context.Context, unitName string, follow bool, numLines int) (<-chan string, <-chan error) {
-	logCh := make(chan string)
-	errCh := make(chan error, 1)
+// hasCursor reports whether the query starts at a cursor. journalctl refuses --since
+// together with a cursor, so the start time is then applied to the parsed entries.
+func (q journalQuery) hasCursor() bool {
+	return q.afterCursor != "" || q.cursor != ""
+}
 
-	go func() {
-		defer close(logCh)
-		defer close(errCh)
+// beforeSince reports whether an entry is older than the start time of a query that
+// starts at a cursor. Like --since, the start time is applied with second precision.
+func (q journalQuery) beforeSince(entry types.LogEntry) bool {
+	if q.filter.Since.IsZero() || !q.hasCursor() {
+		return false
+	}
+	t, err := time.Parse(logTimeFormat, entry.Time)
+	return err == nil && t.Before(q.filter.Since.Truncate(time.Second))
+}
 
-		// Ensure numLines is at least 1
-		count := numLines
-		if count <= 0 {
-			count = 1
-		}
+// resumeAfter returns the query continuing after the entry with cursor. journalctl refuses
+// --since together with a cursor, and every entry after the cursor is newer anyway.
+func (q journalQuery) resumeAfter(cursor string) journalQuery {
+	q.afterCursor = cursor
+	q.cursor = ""
+	q.filter.Since = time.Time{}
+	return q
+}
 
-		s.logger.Info("starting journalctl log streaming",
-			"unit", unitName,
-			"lines", count)
+// StreamServiceLogs retrieves the last numLines journal entries of a unit matching filter and,
+// if follow is set and the filter allows new entries, keeps streaming them as they arrive
+func (s *SystemdService) StreamServiceLogs(ctx context.Context, unitName string, follow bool, numLines int, filter types.LogFilter) (<-chan types.LogEntry, <-chan error) {
+	// Ensure numLines is at least 1
+	count := numLines
+	if count <= 0 {
+		count = 1
+	}
 
-		// Check if context is already canceled
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			// Continue with normal operation
-		}
+	follow = follow && filter.Follows()
 
-		// Start journalctl process with appropriate flags
-		// -u: unit name
-		// -f: follow (real-time updates)
-		// -n: number of lines
-		// -o: output format (short-iso includes timestamps)
-		cmd := exec.CommandContext(ctx, "journalctl", "-u", unitName, "-f", "-n", fmt.Sprintf("%d", count), "-o", "short-iso")
+	s.logger.Info("starting journal log streaming",
+		"unit", unitName,
+		"lines", count,
+		"follow", follow)
 
-		// Get stdout pipe
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			errCh <- fmt.Errorf("failed to get stdout pipe: %w", err)
-			return
-		}
+	return s.streamJournal(ctx, journalQuery{
+		matches: s.journalUnitArgs(unitName),
+		lines:   count,
+		follow:  follow,
+		filter:  filter,
+	})
+}
 
-		// Get stderr pipe
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			errCh <- fmt.Errorf("failed to get stderr pipe: %w", err)
-			return
+// StreamSystemLogs retrieves the last numLines entries of the whole journal matching filter and,
+// if follow is set and the filter allows new entries, keeps streaming them as they arrive.
+// kernel limits the entries to kernel messages like journalctl -k, identifiers to messages
+// logged with any of the given syslog identifiers.
+func (s *SystemdService) StreamSystemLogs(ctx context.Context, kernel bool, identifiers []string, follow bool, numLines int, filter types.LogFilter) (<-chan types.LogEntry, <-chan error) {
+	count := numLines
+	if count <= 0 {
+		count = 1
+	}
+
+	follow = follow && filter.Follows()
+
+	s.logger.Info("starting system journal streaming",
+		"kernel", kernel,
+		"identifiers", identifiers,
+		"lines", count,
+		"follow", follow)
+
+	return s.streamJournal(ctx, journalQuery{
+		matches: journalSystemArgs(kernel, identifiers),
+		lines:   count,
+		follow:  follow,
+		filter:  filter,
+	})
+}
+
+// journalSystemArgs returns the journalctl arguments selecting kernel messages and syslog identifiers
+func journalSystemArgs(kernel bool, identifiers []string) []string {
+	var args []string
+	if kernel {
+		args = append(args, "--dmesg")
+	}
+	for _, identifier := range identifiers {
+		args = append(args, "--identifier", identifier)
+	}
+	return args
+}
+
+// ExportServiceLogs sends all journal entries of a unit matching filter, oldest first
+func (s *SystemdService) ExportServiceLogs(ctx context.Context, unitName string, filter types.LogFilter) (<-chan types.LogEntry, <-chan error) {
+	s.logger.Info("starting journal log export", "unit", unitName)
+
+	return s.streamJournal(ctx, journalQuery{
+		matches: s.journalUnitArgs(unitName),
+		filter:  filter,
+	})
+}
+
+// ExportServiceJournal writes all journal entries of a unit matching filter to w in the
+// journal export format, with every field journalctl knows of. Grep is not applied as
+// the entries are not parsed.
+func (s *SystemdService) ExportServiceJournal(ctx context.Context, unitName string, filter types.LogFilter, w io.Writer) error {
+	s.logger.Info("starting journal export", "unit", unitName)
+
+	query := journalQuery{
+		matches: s.journalUnitArgs(unitName),
+		filter:  filter,
+		output:  "export",
+	}
+	cmd := exec.CommandContext(ctx, "journalctl", query.args()...)
+	cmd.Stdout = w
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return fmt.Errorf("journalctl failed: %s", text)
 		}
+		return fmt.Errorf("journalctl failed: %w", err)
+	}
+	return nil
+}
 
-		// Start the command
-		if err := cmd.Start(); err != nil {
+// streamJournal sends the entries selected by query. When following, journalctl is
+// restarted after the last received cursor if it exits, so no entries are lost or repeated.
+// Failures are reported on the error channel; restarts back off while journalctl keeps
+// failing without reading anything, and the stream ends after maxJournalFailures of them.
+func (s *SystemdService) streamJournal(ctx context.Context, query journalQuery) (<-chan types.LogEntry, <-chan error) {
+	logCh := make(chan types.LogEntry)
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(logCh)
+		defer close(errCh)
+
+		delay := journalRestartDelay
+		failures := 0
+		for {
+			cursor, err := s.readJournal(ctx, query, logCh)
 			if ctx.Err() != nil {
-				// Context was canceled, just return
 				return
 			}
-			errCh <- fmt.Errorf("failed to start journalctl: %w", err)
-			return
-		}
-
-		// We still need to cancel any resources when the function returns
-		_, cancel := context.WithCancel(ctx)
-		defer cancel()
+			if !query.follow || errors.Is(err, errJournalStart) {
+				if err != nil {
+					errCh <- err
+				}
+				return
+			}
 
-		// Handle stderr in a separate goroutine
-		go func() {
-			scanner := bufio.NewScanner(stderr)
-			for scanner.Scan() {
-				errText := scanner.Text()
-				s.logger.Warn("journalctl stderr", "text", errText)
+			if cursor != "" {
+				query = query.resumeAfter(cursor)
 			}
-		}()
-
-		// Process stdout
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			// Parse the log entry
-			entry, err := s.parseJournalctlLine(line, unitName)
-			if err != nil {
-				s.logger.Warn("failed to parse journalctl line", "error", err, "line", line)
-				continue
+			if err == nil || cursor != "" {
+				delay = journalRestartDelay
+				failures = 0
+			} else {
+				failures++
+				if failures >= maxJournalFailures {
+					select {
+					case errCh <- fmt.Errorf("giving up after %d consecutive journalctl failures: %w", failures, err):
+					case <-ctx.Done():
+					}
+					return
+				}
+				// Don't block on a client that has not read the previous error yet
+				select {
+				case errCh <- err:
+				default:
+				}
 			}
 
+			s.logger.Info("journalctl exited while following, restarting",
+				"error", err,
+				"delay", delay)
+
 			select {
-			case logCh <- entry:
 			case <-ctx.Done():
-				// Context canceled, stop processing gracefully
-				if cmd.Process != nil {
-					if err := cmd.Process.Kill(); err != nil {
-						s.logger.Warn("failed to kill journalctl process on context done", "error", err)
-					}
-				}
 				return
+			case <-time.After(delay):
+			}
+			if failures > 0 {
+				delay = min(delay*2, maxJournalRestartDelay)
 			}
 		}
+	}()
 
-		// Check for scanner errors
-		if err := scanner.Err(); err != nil {
-			s.logger.Warn("scanner error", "error", err)
-		}
+	return logCh, errCh
+}
 
-		// Wait for the command to finish
-		err = cmd.Wait()
+// GetServiceLogHistory returns a page of at most limit journal entries of a unit matching
+// filter that are older than the before cursor, newer than the after cursor, or the latest ones
+func (s *SystemdService) GetServiceLogHistory(ctx context.Context, unitName, before, after string, limit int, filter types.LogFilter) (*types.LogPage, error) {
+	query := journalQuery{
+		matches: s.journalUnitArgs(unitName),
+		filter:  filter,
+		// Without an after cursor the page ends at the cursor or the tail of the journal
+		reverse: after == "",
+	}
 
-		// If the context was canceled, this is expected
-		if ctx.Err() != nil {
-			return
+	var err error
+	if after != "" {
+		if query.afterCursor, err = decodeLogCursor(after); err != nil {
+			return nil, err
 		}
+	} else if before != "" {
+		if query.cursor, err = decodeLogCursor(before); err != nil {
+			return nil, err
+		}
+	}
 
-		// If we get here and there's no context error, the journalctl process exited unexpectedly
-		// Start a new journalctl process
-		s.logger.Info("journalctl process exited, restarting", "unit", unitName, "error", err)
+	entries, err := s.collectJournal(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
 
-		// Small delay before restarting
-		select {
-		case <-ctx.Done():
-			return
-		case <-time.After(500 * time.Millisecond):
+	page := &types.LogPage{Entries: entries}
+	if len(entries) == 0 {
+		page.After = after
+		return page, nil
+	}
+	// A short page read backwards has reached the start of the journal
+	if !query.reverse || len(entries) == limit {
+		page.Before = encodeLogCursor(entries[0].Cursor)
+	}
+	page.After = encodeLogCursor(entries[len(entries)-1].Cursor)
+
+	return page, nil
+}
+
+// collectJournal reads up to limit matching entries for query and returns them in
+// chronological order, stopping journalctl as soon as enough entries were read
+func (s *SystemdService) collectJournal(ctx context.Context, query journalQuery, limit int) ([]types.LogEntry, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logCh := make(chan types.LogEntry)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(logCh)
+		_, err := s.readJournal(ctx, query, logCh)
+		errCh <- err
+	}()
+
+	entries := make([]types.LogEntry, 0, limit)
+	for entry := range logCh {
+		// --cursor includes the entry the cursor points at, which belongs to the previous page
+		if query.cursor != "" && entry.Cursor == query.cursor {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) == limit {
+			cancel()
+			break
 		}
+	}
+	for range logCh {
+	}
 
-		// Recursively call StreamServiceLogs to restart the process
-		newLogCh, newErrCh := s.StreamServiceLogs(ctx, unitName, follow, 10) // Only get the last 10 lines on restart
+	if err := <-errCh; err != nil && ctx.Err() == nil {
+		return nil, err
+	}
 
-		// Forward logs and errors from the new channels
-		for {
-			select {
-			case log, ok := <-newLogCh:
-				if !ok {
-					return
-				}
-				logCh <- log
-			case err, ok := <-newErrCh:
-				if !ok {
-					return
-				}
-				errCh <- err
-			case <-ctx.Done():
-				return
-			}
+	if query.reverse {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
 		}
-	}()
+	}
 
-	return logCh, errCh
+	return entries, nil
+}
+
+// encodeLogCursor turns a journal cursor into an opaque, URL-safe history cursor
+func encodeLogCursor(cursor string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
 }
 
-// parseJournalctlLine parses a line from journalctl output
-// Format example: "2023-03-17T20:53:05+0100 hostname systemd[1]: Started My Service."
-func (s *SystemdService) parseJournalctlLine(line string, unitName string) (string, error) {
-	// Find the first space which separates the timestamp from the rest
-	timestampEndIndex := strings.Index(line, " ")
-	if timestampEndIndex <= 0 {
-		return "", fmt.Errorf("invalid journalctl line format: %s", line)
+// decodeLogCursor returns the journal cursor of a history cursor
+func decodeLogCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(decoded) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
 	}
+	return string(decoded), nil
+}
 
-	// Extract timestamp string directly without parsing
-	timestampStr := line[:timestampEndIndex]
+// readJournal runs journalctl once for query and sends its matching entries to logCh.
+// It returns the cursor of the last entry read.
+func (s *SystemdService) readJournal(ctx context.Context, query journalQuery, logCh chan<- types.LogEntry) (string, error) {
+	cmd := exec.CommandContext(ctx, "journalctl", query.args()...)
 
-	// Extract message (everything after the timestamp)
-	fullMessage := strings.TrimSpace(line[timestampEndIndex+1:])
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errJournalStart, err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 
-	// Split the message by spaces
-	parts := strings.SplitN(fullMessage, " ", 2)
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("%w: %w", errJournalStart, err)
+	}
+
+	var cursor string
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxJournalEntrySize)
+	for scanner.Scan() {
+		entry, err := parseJournalEntry(scanner.Bytes())
+		if err != nil {
+			s.logger.Warn("failed to parse journal entry", "error", err)
+			continue
+		}
+		// Skipped entries still count as read when journalctl has to be restarted
+		cursor = entry.Cursor
+		if query.beforeSince(entry) {
+			if query.reverse {
+				// Reading backwards, all remaining entries are older as well
+				_ = cmd.Process.Kill()
+				_ = cmd.Wait()
+				return cursor, nil
+			}
+			continue
+		}
+		if !query.filter.Matches(entry.Message) {
+			continue
+		}
 
-	// If we have at least 2 parts, the first part is the hostname, so skip it
-	message := fullMessage
-	if len(parts) >= 2 {
-		// Skip the hostname, keep everything else
-		message = parts[1]
+		select {
+		case logCh <- entry:
+		case <-ctx.Done():
+			// CommandContext kills journalctl, wait for it to release its resources
+			_ = cmd.Wait()
+			return cursor, ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		s.logger.Warn("failed to read journal output", "error", err)
 	}
 
-	// Create log entry
-	entry := LogEntry{
-		Timestamp: timestampStr,
-		Message:   message,
-		UnitName:  unitName,
+	if err := cmd.Wait(); err != nil {
+		if text := strings.TrimSpace(stderr.String()); text != "" {
+			return cursor, fmt.Errorf("journalctl failed: %s", text)
+		}
+		return cursor, fmt.Errorf("journalctl failed: %w", err)
+	}
+	if text := strings.TrimSpace(stderr.String()); text != "" {
+		s.logger.Warn("journalctl stderr", "text", text)
 	}
 
-	return entry.String(), nil
+	return cursor, nil
+}
+
+// parseJournalEntry converts an entry of journalctl's JSON output into a log entry
+func parseJournalEntry(data []byte) (types.LogEntry, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return types.LogEntry{}, fmt.Errorf("invalid journal entry: %w", err)
+	}
+
+	entry := types.LogEntry{
+		Message:          journalFieldString(fields[journalMessageField]),
+		SyslogIdentifier: journalFieldString(fields[journalIdentifierField]),
+		Unit:             journalFieldString(fields[journalUnitField]),
+		Cursor:           journalFieldString(fields[journalCursorField]),
+		BootID:           journalFieldString(fields[journalBootIDField]),
+	}
+	if entry.Unit == "" {
+		entry.Unit = journalFieldString(fields[journalUserUnitField])
+	}
+
+	if usec, err := strconv.ParseInt(journalFieldString(fields[journalRealtimeField]), 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec).Format(logTimeFormat)
+	}
+	if priority, err := strconv.Atoi(journalFieldString(fields[journalPriorityField])); err == nil {
+		entry.Priority = &priority
+	}
+	if pid, err := strconv.Atoi(journalFieldString(fields[journalPIDField])); err == nil {
+		entry.PID = pid
+	}
+
+	return entry, nil
+}
+
+// journalFieldString returns the value of a journal field as a string. journalctl
+// encodes binary values as byte arrays and repeated fields as arrays of values;
+// for the latter the first value is used.
+func journalFieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any:
+		if len(v) == 0 {
+			return ""
+		}
+		if _, ok := v[0].(float64); !ok {
+			return journalFieldString(v[0])
+		}
+		data := make([]byte, 0, len(v))
+		for _, b := range v {
+			n, ok := b.(float64)
+			if !ok {
+				return ""
+			}
+			data = append(data, byte(n))
+		}
+		return string(data)
+	}
+	return ""
 }
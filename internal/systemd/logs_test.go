@@ -2,11 +2,15 @@ package systemd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
 )
 
 // TestLogStreaming tests the log streaming functionality in more detail
@@ -33,9 +37,9 @@ func TestLogStreaming(t *testing.T) {
 		if err == nil && details.Service.ActiveState == "active" {
 			// Try to get some logs
 			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			logCh, errCh := s.StreamServiceLogs(ctx, svc, false, 5)
+			logCh, errCh := s.StreamServiceLogs(ctx, svc, false, 5, types.LogFilter{})
 
-			var logs []string
+			var logs []types.LogEntry
 			var streamErr error
 
 		collectLogs:
@@ -82,9 +86,9 @@ func TestLogStreaming(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 				defer cancel()
 
-				logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, false, count)
+				logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, false, count, types.LogFilter{})
 
-				var logs []string
+				var logs []types.LogEntry
 				var streamErr error
 
 			collectLogs:
@@ -119,7 +123,7 @@ func TestLogStreaming(t *testing.T) {
 				// Log a few entries for debugging
 				for i, log := range logs {
 					if i < 3 {
-						t.Logf("Log entry %d: %s", i, log)
+						t.Logf("Log entry %d: %+v", i, log)
 					}
 				}
 			})
@@ -131,9 +135,9 @@ func TestLogStreaming(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
 
-		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, false, 5)
+		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, false, 5, types.LogFilter{})
 
-		var logs []string
+		var logs []types.LogEntry
 		var streamErr error
 
 	collectLogs:
@@ -165,27 +169,18 @@ func TestLogStreaming(t *testing.T) {
 			t.Skip("No logs found to test format")
 		}
 
-		// Check log format (should be timestamp followed by message)
+		// Check that entries carry the structured journal fields
 		for i, log := range logs {
 			if i >= 3 {
 				break // Only check a few logs
 			}
 
-			// Log entries should have a timestamp followed by a message
-			// The format is: "timestamp: message"
-			parts := strings.SplitN(log, ": ", 2)
-			if len(parts) != 2 {
-				t.Errorf("Log entry doesn't have expected format: %s", log)
-				continue
-			}
-
-			timestamp, message := parts[0], parts[1]
-			if timestamp == "" {
-				t.Errorf("Log timestamp is empty: %s", log)
+			if log.Time == "" {
+				t.Errorf("Log timestamp is empty: %+v", log)
 			}
 
-			if message == "" {
-				t.Errorf("Log message is empty: %s", log)
+			if log.Cursor == "" {
+				t.Errorf("Log cursor is empty: %+v", log)
 			}
 		}
 	})
@@ -196,13 +191,13 @@ func TestLogStreaming(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, true, 1)
+		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, true, 1, types.LogFilter{})
 
 		// Just verify we can read from the channel
 		select {
 		case log, ok := <-logCh:
 			if ok {
-				t.Logf("Got log in follow mode: %s", log)
+				t.Logf("Got log in follow mode: %+v", log)
 			}
 		case err := <-errCh:
 			if err != nil && err != context.DeadlineExceeded && err != context.Canceled {
@@ -218,7 +213,7 @@ func TestLogStreaming(t *testing.T) {
 	t.Run("ContextCancellation", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 
-		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, true, 1)
+		logCh, errCh := s.StreamServiceLogs(ctx, serviceWithLogs, true, 1, types.LogFilter{})
 
 		// Cancel the context immediately
 		cancel()
@@ -243,47 +238,182 @@ func TestLogStreaming(t *testing.T) {
 	})
 }
 
-func TestLogEntryString(t *testing.T) {
+func TestParseJournalEntry(t *testing.T) {
+	line := `{"__CURSOR":"s=abc;i=1","_BOOT_ID":"8f2b","__REALTIME_TIMESTAMP":"1672671845123456","PRIORITY":"3","_PID":"42","SYSLOG_IDENTIFIER":"nginx","_SYSTEMD_UNIT":"nginx.service","MESSAGE":"upstream timed out"}`
+
+	entry, err := parseJournalEntry([]byte(line))
+	if err != nil {
+		t.Fatalf("parseJournalEntry() failed: %v", err)
+	}
+
+	expectedTime := time.UnixMicro(1672671845123456).Format(logTimeFormat)
+	if entry.Time != expectedTime {
+		t.Errorf("Time = %q, want %q", entry.Time, expectedTime)
+	}
+	if entry.Priority == nil || *entry.Priority != 3 {
+		t.Errorf("Priority = %v, want 3", entry.Priority)
+	}
+	if entry.PID != 42 {
+		t.Errorf("PID = %d, want 42", entry.PID)
+	}
+	if entry.SyslogIdentifier != "nginx" || entry.Unit != "nginx.service" {
+		t.Errorf("SyslogIdentifier = %q, Unit = %q", entry.SyslogIdentifier, entry.Unit)
+	}
+	if entry.Message != "upstream timed out" || entry.Cursor != "s=abc;i=1" || entry.BootID != "8f2b" {
+		t.Errorf("Message = %q, Cursor = %q, BootID = %q", entry.Message, entry.Cursor, entry.BootID)
+	}
+
+	// Binary messages are encoded as byte arrays, user units use their own field
+	entry, err = parseJournalEntry([]byte(`{"MESSAGE":[104,105],"_SYSTEMD_USER_UNIT":"app.service"}`))
+	if err != nil {
+		t.Fatalf("parseJournalEntry() failed: %v", err)
+	}
+	if entry.Message != "hi" || entry.Unit != "app.service" || entry.Priority != nil {
+		t.Errorf("parseJournalEntry() = %+v", entry)
+	}
+
+	if _, err := parseJournalEntry([]byte("Jan 02 15:04:05 host nginx[42]: text")); err == nil {
+		t.Error("parseJournalEntry() should fail for non-JSON output")
+	}
+}
+
+func TestParseBootList(t *testing.T) {
+	first := time.UnixMicro(1704103200000000).Format(logTimeFormat)
+	last := time.UnixMicro(1704110400000000).Format(logTimeFormat)
+
+	testCases := []struct {
+		name   string
+		output string
+	}{
+		{"JSON", `[{"index":-1,"boot_id":"8f2b0c1d","first_entry":1704103200000000,"last_entry":1704110400000000}]`},
+		{"Text", "IDX BOOT ID  FIRST ENTRY                 LAST ENTRY\n -1 8f2b0c1d Mon 2024-01-01 10:00:00 UTC Mon 2024-01-01 12:00:00 UTC\n"},
+		{"LegacyText", " -1 8f2b0c1d Mon 2024-01-01 10:00:00 UTC—Mon 2024-01-01 12:00:00 UTC\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			boots, err := parseBootList([]byte(tc.output))
+			if err != nil {
+				t.Fatalf("parseBootList() failed: %v", err)
+			}
+			if len(boots) != 1 {
+				t.Fatalf("parseBootList() returned %d boots, want 1", len(boots))
+			}
+			boot := boots[0]
+			if boot.Index != -1 || boot.ID != "8f2b0c1d" || boot.FirstEntry != first || boot.LastEntry != last {
+				t.Errorf("parseBootList() = %+v, want index -1 from %s to %s", boot, first, last)
+			}
+		})
+	}
+}
+
+func TestJournalQueryArgs(t *testing.T) {
 	testCases := []struct {
 		name     string
-		entry    LogEntry
+		query    journalQuery
 		expected string
 	}{
-		{
-			name: "Normal entry",
-			entry: LogEntry{
-				Timestamp: "2023-01-02T15:04:05Z",
-				Message:   "Test message",
-				UnitName:  "test.service",
-			},
-			expected: "2023-01-02T15:04:05Z: Test message",
-		},
-		{
-			name: "Empty message",
-			entry: LogEntry{
-				Timestamp: "2023-01-02T15:04:05Z",
-				Message:   "",
-				UnitName:  "test.service",
-			},
-			expected: "2023-01-02T15:04:05Z: ",
-		},
-		{
-			name: "With timezone",
-			entry: LogEntry{
-				Timestamp: "2023-01-02T15:04:05+01:00",
-				Message:   "Test message",
-				UnitName:  "test.service",
-			},
-			expected: "2023-01-02T15:04:05+01:00: Test message",
-		},
+		{"Lines", journalQuery{matches: []string{"--unit", "nginx.service"}, lines: 10}, "--output json --unit nginx.service --lines 10"},
+		{"Follow", journalQuery{lines: 1, follow: true}, "--output json --lines 1 --follow"},
+		{"AfterCursor", journalQuery{lines: 10, follow: true, afterCursor: "s=abc"}, "--output json --after-cursor s=abc --follow"},
+		{"Cursor", journalQuery{cursor: "s=abc", reverse: true}, "--output json --cursor s=abc --reverse"},
+		{"Filter", journalQuery{lines: 5, filter: types.LogFilter{
+			Since:    time.Unix(1700000000, 0),
+			Until:    time.Unix(1700003600, 0),
+			Priority: "3",
+			Boot:     "-1",
+		}}, "--output json --lines 5 --since @1700000000 --until @1700003600 --priority 3 --boot -1"},
+		{"SinceWithCursor", journalQuery{cursor: "s=abc", reverse: true, filter: types.LogFilter{
+			Since: time.Unix(1700000000, 0),
+		}}, "--output json --cursor s=abc --reverse"},
+		{"SinceWithAfterCursor", journalQuery{afterCursor: "s=abc", filter: types.LogFilter{
+			Since:    time.Unix(1700000000, 0),
+			Priority: "3",
+		}}, "--output json --after-cursor s=abc --priority 3"},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := tc.entry.String()
-			if result != tc.expected {
-				t.Errorf("LogEntry.String() = %q, want %q", result, tc.expected)
+			got := fmt.Sprint(tc.query.args())
+			if got != "["+tc.expected+"]" {
+				t.Errorf("args() = %s, want [%s]", got, tc.expected)
 			}
 		})
 	}
 }
+
+func TestJournalQueryBeforeSince(t *testing.T) {
+	since := time.Unix(1700000000, 500_000_000)
+	older := types.LogEntry{Time: time.Unix(1699999999, 0).Format(logTimeFormat)}
+	sameSecond := types.LogEntry{Time: time.Unix(1700000000, 0).Format(logTimeFormat)}
+
+	cursorQuery := journalQuery{cursor: "s=abc", filter: types.LogFilter{Since: since}}
+	if !cursorQuery.beforeSince(older) {
+		t.Error("expected entry before since to be skipped when starting at a cursor")
+	}
+	if cursorQuery.beforeSince(sameSecond) {
+		t.Error("expected entry in the second of since to be kept")
+	}
+	if (journalQuery{filter: types.LogFilter{Since: since}}).beforeSince(older) {
+		t.Error("expected journalctl to apply since without a cursor")
+	}
+}
+
+func TestJournalQueryResumeAfter(t *testing.T) {
+	query := journalQuery{
+		matches: []string{"--unit", "nginx.service"},
+		lines:   10,
+		follow:  true,
+		filter:  types.LogFilter{Since: time.Unix(1700000000, 0), Priority: "3"},
+	}
+
+	got := fmt.Sprint(query.resumeAfter("s=abc").args())
+	if want := "[--output json --unit nginx.service --after-cursor s=abc --follow --priority 3]"; got != want {
+		t.Errorf("resumeAfter().args() = %s, want %s", got, want)
+	}
+}
+
+func TestJournalSystemArgs(t *testing.T) {
+	if args := journalSystemArgs(false, nil); len(args) != 0 {
+		t.Errorf("journalSystemArgs() = %v, want no arguments for the whole journal", args)
+	}
+	got := fmt.Sprint(journalSystemArgs(true, []string{"kernel", "systemd-oomd"}))
+	if want := "[--dmesg --identifier kernel --identifier systemd-oomd]"; got != want {
+		t.Errorf("journalSystemArgs() = %s, want %s", got, want)
+	}
+}
+
+func TestLogCursor(t *testing.T) {
+	cursor := "s=0f1e;i=2a;b=9c;m=1f;t=5e;x=3d"
+	encoded := encodeLogCursor(cursor)
+	if strings.ContainsAny(encoded, "=;&") {
+		t.Errorf("encodeLogCursor() = %q, want URL-safe cursor", encoded)
+	}
+	decoded, err := decodeLogCursor(encoded)
+	if err != nil || decoded != cursor {
+		t.Errorf("decodeLogCursor() = %q, %v, want %q", decoded, err, cursor)
+	}
+
+	for _, invalid := range []string{"", "not a cursor!"} {
+		if _, err := decodeLogCursor(invalid); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("decodeLogCursor(%q) error = %v, want ErrInvalidCursor", invalid, err)
+		}
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	filter := types.LogFilter{Grep: regexp.MustCompile("(?i)timed out")}
+	if !filter.Matches("upstream Timed Out") {
+		t.Error("expected case-insensitive grep to match")
+	}
+	if filter.Matches("request finished") {
+		t.Error("expected grep not to match unrelated message")
+	}
+	if !(types.LogFilter{}).Matches("anything") {
+		t.Error("expected empty filter to match every message")
+	}
+
+	if !(types.LogFilter{Boot: "0"}).Follows() || (types.LogFilter{Boot: "-1"}).Follows() || (types.LogFilter{Until: time.Now()}).Follows() {
+		t.Error("expected only filters without end time and earlier boot to follow")
+	}
+}
@@ -0,0 +1,221 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrVerificationFailed is returned when systemd-analyze verify rejects a unit
+	ErrVerificationFailed = errors.New("unit verification failed")
+	// ErrInvalidUnitFileName is returned for unit or drop-in names that are not safe to write
+	ErrInvalidUnitFileName = errors.New("invalid unit file name")
+)
+
+var overrideNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.@-]*$`)
+
+// WriteOverride writes a drop-in override for a unit, verifies the unit and reloads the daemon.
+// The override is verified in a staging directory and only moved into place if it passes.
+func (s *SystemdService) WriteOverride(unit, override, content string) error {
+	path, err := overridePath(adminUnitDir, unit, override)
+	if err != nil {
+		return err
+	}
+
+	s.overrideMu.Lock()
+	defer s.overrideMu.Unlock()
+
+	staged, err := stageDropIn(path, []byte(content))
+	if err != nil {
+		return err
+	}
+	defer staged.remove()
+
+	if err := s.verifyStaged(staged, unit); err != nil {
+		return err
+	}
+	if err := staged.commit(); err != nil {
+		return fmt.Errorf("failed to write override %s: %w", path, err)
+	}
+
+	return s.reloadDaemon()
+}
+
+// DeleteOverride removes a drop-in override for a unit, verifies the unit and reloads the daemon.
+// The unit is verified with the override masked by an empty staged drop-in before it is removed.
+func (s *SystemdService) DeleteOverride(unit, override string) error {
+	path, err := overridePath(adminUnitDir, unit, override)
+	if err != nil {
+		return err
+	}
+
+	s.overrideMu.Lock()
+	defer s.overrideMu.Unlock()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fmt.Errorf("override %s not found", path)
+	}
+
+	staged, err := stageDropIn(path, nil)
+	if err != nil {
+		return err
+	}
+	defer staged.remove()
+
+	if err := s.verifyStaged(staged, unit); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove override %s: %w", path, err)
+	}
+	removeEmptyDir(filepath.Dir(path))
+
+	return s.reloadDaemon()
+}
+
+// verifyAndReload verifies units after their files changed and reloads the daemon,
+// calling restore to roll back the change if verification fails
+func (s *SystemdService) verifyAndReload(restore func() error, units ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	if err := s.verifyUnits(ctx, "", units...); err != nil {
+		if restoreErr := restore(); restoreErr != nil {
+			s.logger.Error("failed to roll back unit files after failed verification",
+				"units", units,
+				"error", restoreErr)
+		}
+		return err
+	}
+
+	return s.reloadDaemon()
+}
+
+// verifyStaged verifies units with a staged drop-in taking precedence over the installed files
+func (s *SystemdService) verifyStaged(staged *stagedDropIn, units ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	return s.verifyUnits(ctx, staged.unitPath(), units...)
+}
+
+// reloadDaemon makes systemd pick up changed unit files
+func (s *SystemdService) reloadDaemon() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.ReloadContext(ctx); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+
+	return nil
+}
+
+// verifyUnits runs systemd-analyze verify against units. If unitPath is set, it is used
+// as SYSTEMD_UNIT_PATH, otherwise the units are verified as they are installed.
+func (s *SystemdService) verifyUnits(ctx context.Context, unitPath string, units ...string) error {
+	args := append([]string{"verify"}, units...)
+	cmd := exec.CommandContext(ctx, "systemd-analyze", args...)
+	if unitPath != "" {
+		cmd.Env = append(os.Environ(), "SYSTEMD_UNIT_PATH="+unitPath)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		s.logger.Warn("unit verification failed",
+			"units", units,
+			"output", string(output),
+			"error", err)
+		return fmt.Errorf("%w for %s: %s", ErrVerificationFailed, strings.Join(units, ", "), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// overridePath returns the path of a drop-in override below dir, rejecting
+// unit and override names that could escape the drop-in directory
+func overridePath(dir, unit, override string) (string, error) {
+	if UnitType(unit) == "" || strings.ContainsAny(unit, "/\x00") {
+		return "", fmt.Errorf("%w: unit %q", ErrInvalidUnitFileName, unit)
+	}
+
+	if !strings.HasSuffix(override, ".conf") {
+		override += ".conf"
+	}
+	if !overrideNamePattern.MatchString(override) {
+		return "", fmt.Errorf("%w: override %q", ErrInvalidUnitFileName, override)
+	}
+
+	return filepath.Join(dir, unit+".d", override), nil
+}
+
+// stagedDropIn is a drop-in written to a staging directory inside the unit directory, so it
+// can be verified before it replaces the installed drop-in with the same name
+type stagedDropIn struct {
+	dir    string
+	path   string
+	target string
+}
+
+// stageDropIn writes content to the staging copy of the drop-in at target. The staging
+// directory is hidden, so systemd does not load it, and on the same file system as target,
+// so committing is an atomic rename.
+func stageDropIn(target string, content []byte) (*stagedDropIn, error) {
+	dropInDir := filepath.Dir(target)
+	dir, err := os.MkdirTemp(filepath.Dir(dropInDir), ".sirberus-staging-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	staged := &stagedDropIn{
+		dir:    dir,
+		path:   filepath.Join(dir, filepath.Base(dropInDir), filepath.Base(target)),
+		target: target,
+	}
+	if err := os.MkdirAll(filepath.Dir(staged.path), 0755); err != nil {
+		staged.remove()
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if err := os.WriteFile(staged.path, content, 0644); err != nil {
+		staged.remove()
+		return nil, fmt.Errorf("failed to stage %s: %w", target, err)
+	}
+
+	return staged, nil
+}
+
+// unitPath returns the SYSTEMD_UNIT_PATH that puts the staging directory before the
+// default search path, which the trailing colon appends
+func (d *stagedDropIn) unitPath() string {
+	return d.dir + ":"
+}
+
+// commit moves the staged drop-in into place
+func (d *stagedDropIn) commit() error {
+	if err := os.MkdirAll(filepath.Dir(d.target), 0755); err != nil {
+		return err
+	}
+	return os.Rename(d.path, d.target)
+}
+
+// remove deletes the staging directory and anything left in it
+func (d *stagedDropIn) remove() {
+	os.RemoveAll(d.dir)
+}
+
+// removeEmptyDir removes a drop-in directory once its last file is gone
+func removeEmptyDir(dir string) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+		os.Remove(dir)
+	}
+}
@@ -0,0 +1,271 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrInvalidProperty is returned for unknown resource properties or values that cannot be parsed
+var ErrInvalidProperty = errors.New("invalid property")
+
+// ErrPropertiesPartiallyApplied is returned when the runtime changes of a request were applied
+// but setting its persistent changes failed
+var ErrPropertiesPartiallyApplied = errors.New("runtime properties were applied, persistent properties were not")
+
+// Resource properties that can be changed at runtime
+const (
+	resourceCPUQuota    = "CPUQuota"
+	resourceMemoryMax   = "MemoryMax"
+	resourceMemoryHigh  = "MemoryHigh"
+	resourceTasksMax    = "TasksMax"
+	resourceIOWeight    = "IOWeight"
+	resourceAllowedCPUs = "AllowedCPUs"
+
+	limitInfinity = "infinity"
+)
+
+// SetUnitProperties applies resource property changes to a unit. Changes are lost on reboot
+// unless they are persistent, in which case systemd writes them to a drop-in.
+func (s *SystemdService) SetUnitProperties(name string, changes []types.SystemdPropertyChange) error {
+	if len(changes) == 0 {
+		return fmt.Errorf("%w: no changes given", ErrInvalidProperty)
+	}
+
+	runtimeProps := []dbus.Property{}
+	persistentProps := []dbus.Property{}
+	for _, change := range changes {
+		property, err := resourceProperty(change.Name, change.Value)
+		if err != nil {
+			return err
+		}
+
+		if change.Persistent {
+			persistentProps = append(persistentProps, property)
+		} else {
+			runtimeProps = append(runtimeProps, property)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if len(runtimeProps) > 0 {
+		if err := conn.SetUnitPropertiesContext(ctx, name, true, runtimeProps...); err != nil {
+			return fmt.Errorf("failed to set runtime properties of unit %s: %w", name, err)
+		}
+	}
+
+	if len(persistentProps) > 0 {
+		if err := conn.SetUnitPropertiesContext(ctx, name, false, persistentProps...); err != nil {
+			// Runtime and persistent changes need separate calls, so the first may already be in effect
+			if len(runtimeProps) > 0 {
+				return fmt.Errorf("%w: failed to set persistent properties of unit %s: %w", ErrPropertiesPartiallyApplied, name, err)
+			}
+			return fmt.Errorf("failed to set persistent properties of unit %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// resourceProperty converts a resource setting as written in a unit file to its D-Bus property
+func resourceProperty(name, value string) (dbus.Property, error) {
+	value = strings.TrimSpace(value)
+
+	switch name {
+	case resourceCPUQuota:
+		quota, err := parseCPUQuota(value)
+		if err != nil {
+			return dbus.Property{}, fmt.Errorf("%w: %s: %v", ErrInvalidProperty, name, err)
+		}
+		return dbus.Property{Name: "CPUQuotaPerSecUSec", Value: godbus.MakeVariant(quota)}, nil
+
+	case resourceMemoryMax, resourceMemoryHigh:
+		bytes := uint64(math.MaxUint64)
+		if value != limitInfinity {
+			var err error
+			if bytes, err = parseByteSize(value); err != nil {
+				return dbus.Property{}, fmt.Errorf("%w: %s: %v", ErrInvalidProperty, name, err)
+			}
+		}
+		return dbus.Property{Name: name, Value: godbus.MakeVariant(bytes)}, nil
+
+	case resourceTasksMax:
+		tasks := uint64(math.MaxUint64)
+		if value != limitInfinity {
+			var err error
+			if tasks, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return dbus.Property{}, fmt.Errorf("%w: %s: invalid number %q", ErrInvalidProperty, name, value)
+			}
+		}
+		return dbus.Property{Name: name, Value: godbus.MakeVariant(tasks)}, nil
+
+	case resourceIOWeight:
+		weight, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || weight < 1 || weight > 10000 {
+			return dbus.Property{}, fmt.Errorf("%w: %s must be between 1 and 10000", ErrInvalidProperty, name)
+		}
+		return dbus.Property{Name: name, Value: godbus.MakeVariant(weight)}, nil
+
+	case resourceAllowedCPUs:
+		mask, err := parseCPUList(value)
+		if err != nil {
+			return dbus.Property{}, fmt.Errorf("%w: %s: %v", ErrInvalidProperty, name, err)
+		}
+		return dbus.Property{Name: name, Value: godbus.MakeVariant(mask)}, nil
+	}
+
+	return dbus.Property{}, fmt.Errorf("%w: unsupported property %q", ErrInvalidProperty, name)
+}
+
+// getResourceLimits extracts the current resource limits from unit properties
+func getResourceLimits(props map[string]interface{}) types.SystemdResourceLimits {
+	limits := types.SystemdResourceLimits{
+		MemoryMax:  formatLimit(getUint64Property(props, "MemoryMax")),
+		MemoryHigh: formatLimit(getUint64Property(props, "MemoryHigh")),
+		TasksMax:   formatLimit(getUint64Property(props, "TasksMax")),
+	}
+
+	if quota := getUint64Property(props, "CPUQuotaPerSecUSec"); quota > 0 && quota != math.MaxUint64 {
+		limits.CPUQuota = fmt.Sprintf("%d%%", quota/10000)
+	}
+
+	if weight := getUint64Property(props, "IOWeight"); weight > 0 && weight != math.MaxUint64 {
+		limits.IOWeight = strconv.FormatUint(weight, 10)
+	}
+
+	if mask, ok := props["AllowedCPUs"].([]byte); ok {
+		limits.AllowedCPUs = formatCPUList(mask)
+	}
+
+	return limits
+}
+
+// formatLimit formats a limit, using "infinity" for unlimited and empty for unset values
+func formatLimit(value uint64) string {
+	switch value {
+	case 0:
+		return ""
+	case math.MaxUint64:
+		return limitInfinity
+	default:
+		return strconv.FormatUint(value, 10)
+	}
+}
+
+// cpuQuotaUSecPerPercent is the CPU time per second in microseconds granted by one percent of quota
+const cpuQuotaUSecPerPercent = 10000
+
+// parseCPUQuota parses a CPU quota percentage to CPU time in microseconds per second
+func parseCPUQuota(quota string) (uint64, error) {
+	if quota == limitInfinity {
+		return math.MaxUint64, nil
+	}
+
+	percent, err := strconv.ParseUint(strings.TrimSuffix(quota, "%"), 10, 64)
+	if err != nil || percent == 0 {
+		return 0, fmt.Errorf("invalid quota %q", quota)
+	}
+	if percent > math.MaxUint64/cpuQuotaUSecPerPercent {
+		return 0, fmt.Errorf("quota %q is too large", quota)
+	}
+	return percent * cpuQuotaUSecPerPercent, nil
+}
+
+// parseByteSize parses a size with an optional binary suffix (K, M, G, T) like systemd does
+func parseByteSize(size string) (uint64, error) {
+	size = strings.TrimSpace(size)
+	if size == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	multiplier := uint64(1)
+	switch strings.ToUpper(size[len(size)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	case "T":
+		multiplier = 1 << 40
+	}
+	if multiplier > 1 {
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseUint(size, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	if value > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("size %q is too large", size)
+	}
+	return value * multiplier, nil
+}
+
+// parseCPUList parses a CPU list such as "0-3,6" to the bitmask used by AllowedCPUs.
+// An empty list clears the restriction.
+func parseCPUList(list string) ([]byte, error) {
+	mask := []byte{}
+	if list == "" {
+		return mask, nil
+	}
+
+	for _, part := range strings.Split(list, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CPU %q", part)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseUint(bounds[1], 10, 16); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU range %q", part)
+			}
+		}
+
+		for cpu := first; cpu <= last; cpu++ {
+			for uint64(len(mask)) <= cpu/8 {
+				mask = append(mask, 0)
+			}
+			mask[cpu/8] |= 1 << (cpu % 8)
+		}
+	}
+	return mask, nil
+}
+
+// formatCPUList formats an AllowedCPUs bitmask as a CPU list such as "0-3,6"
+func formatCPUList(mask []byte) string {
+	ranges := []string{}
+	start := -1
+	for cpu := 0; cpu <= len(mask)*8; cpu++ {
+		set := cpu < len(mask)*8 && mask[cpu/8]&(1<<(cpu%8)) != 0
+		switch {
+		case set && start < 0:
+			start = cpu
+		case !set && start >= 0:
+			if start == cpu-1 {
+				ranges = append(ranges, strconv.Itoa(start))
+			} else {
+				ranges = append(ranges, fmt.Sprintf("%d-%d", start, cpu-1))
+			}
+			start = -1
+		}
+	}
+	return strings.Join(ranges, ",")
+}
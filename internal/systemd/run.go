@@ -0,0 +1,359 @@
+package systemd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// RunTransientUnit runs a command as a transient service or scope, like systemd-run.
+// Output is streamed on the output channel and the final status is sent on the
+// result channel before the output channel is closed.
+func (s *SystemdService) RunTransientUnit(ctx context.Context, req types.SystemdRunRequest) (<-chan string, <-chan error, <-chan types.SystemdRunResult) {
+	outputCh := make(chan string)
+	errCh := make(chan error, 1)
+	resultCh := make(chan types.SystemdRunResult, 1)
+
+	go func() {
+		defer close(outputCh)
+		defer close(errCh)
+		defer close(resultCh)
+
+		// Copied because runService resolves the executable in place
+		cmdParts := slices.Clone(req.Command)
+		if len(cmdParts) == 0 || cmdParts[0] == "" {
+			errCh <- fmt.Errorf("empty command")
+			return
+		}
+
+		properties, err := transientResourceProperties(req)
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		name, err := transientUnitName(req.Scope)
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		var result types.SystemdRunResult
+		if req.Scope {
+			result, err = s.runScope(ctx, name, cmdParts, properties, outputCh)
+		} else {
+			result, err = s.runService(ctx, name, cmdParts, properties, outputCh)
+		}
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		resultCh <- result
+	}()
+
+	return outputCh, errCh, resultCh
+}
+
+// runService starts the command as a transient service and streams its journal output until it exits
+func (s *SystemdService) runService(ctx context.Context, name string, cmdParts []string, properties []dbus.Property, outputCh chan<- string) (types.SystemdRunResult, error) {
+	path, err := exec.LookPath(cmdParts[0])
+	if err != nil {
+		return types.SystemdRunResult{}, fmt.Errorf("failed to find command %s: %w", cmdParts[0], err)
+	}
+	cmdParts[0] = path
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return types.SystemdRunResult{}, err
+	}
+	defer conn.Close()
+
+	// RemainAfterExit keeps the unit loaded after the command exits so its status can be read
+	properties = append(properties,
+		dbus.PropDescription(fmt.Sprintf("Sirberus: %s", strings.Join(cmdParts, " "))),
+		dbus.PropType("exec"),
+		dbus.PropExecStart(cmdParts, false),
+		dbus.PropRemainAfterExit(true),
+	)
+
+	logCtx, logCancel := context.WithCancel(ctx)
+	logCh, logErrCh := s.StreamServiceLogs(logCtx, name, true, maxTransientLogLines, types.LogFilter{})
+
+	var forwarding sync.WaitGroup
+	forwarding.Add(1)
+	go func() {
+		defer forwarding.Done()
+		s.forwardLogs(logCtx, logCh, logErrCh, outputCh)
+	}()
+	// outputCh is closed once this returns, so forwarding must have stopped on every path
+	defer func() {
+		logCancel()
+		forwarding.Wait()
+	}()
+
+	ch := make(chan string, 1)
+	if _, err := conn.StartTransientUnitContext(ctx, name, jobModeFail, properties, ch); err != nil {
+		return types.SystemdRunResult{}, fmt.Errorf("failed to start transient unit %s: %w", name, err)
+	}
+	select {
+	case result := <-ch:
+		if result != jobResultDone {
+			return types.SystemdRunResult{}, fmt.Errorf("failed to start transient unit %s: job result was %s", name, result)
+		}
+	case <-ctx.Done():
+		s.cleanupTransientService(conn, name, "")
+		return types.SystemdRunResult{}, ctx.Err()
+	}
+
+	result, err := s.waitForTransientService(ctx, conn, name)
+	if err != nil {
+		return types.SystemdRunResult{}, err
+	}
+
+	// Give the journal a moment to deliver the last lines before the stream is closed
+	select {
+	case <-ctx.Done():
+	case <-time.After(transientLogFlushDuration):
+	}
+	logCancel()
+	forwarding.Wait()
+
+	s.cleanupTransientService(conn, name, result.Result)
+
+	return result, nil
+}
+
+// waitForTransientService polls a transient service until its main process has exited
+func (s *SystemdService) waitForTransientService(ctx context.Context, conn *dbus.Conn, name string) (types.SystemdRunResult, error) {
+	ticker := time.NewTicker(transientPollInterval)
+	defer ticker.Stop()
+
+	for {
+		props, err := conn.GetUnitTypePropertiesContext(ctx, name, "Service")
+		if err != nil {
+			return types.SystemdRunResult{}, fmt.Errorf("failed to get properties of transient unit %s: %w", name, err)
+		}
+
+		if getUint32Property(props, "MainPID") == 0 && getUint64Property(props, "ExecMainExitTimestamp") > 0 {
+			return types.SystemdRunResult{
+				Unit:   name,
+				Result: getStringProperty(props, "Result"),
+				Code:   exitCodeName(getInt32Property(props, "ExecMainCode")),
+				Status: getInt32Property(props, "ExecMainStatus"),
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			s.cleanupTransientService(conn, name, "")
+			return types.SystemdRunResult{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanupTransientService stops a finished transient service and clears its failed state so it is unloaded
+func (s *SystemdService) cleanupTransientService(conn *dbus.Conn, name, result string) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	if _, err := conn.StopUnitContext(ctx, name, jobModeReplace, nil); err != nil {
+		s.logger.Warn("failed to stop transient unit", "unit", name, "error", err)
+	}
+	if result != "" && result != "success" {
+		if err := conn.ResetFailedUnitContext(ctx, name); err != nil {
+			s.logger.Warn("failed to reset transient unit", "unit", name, "error", err)
+		}
+	}
+}
+
+// runScope starts the command as a child of Sirberus, moves it into a transient scope
+// and streams its stdout and stderr until it exits
+func (s *SystemdService) runScope(ctx context.Context, name string, cmdParts []string, properties []dbus.Property, outputCh chan<- string) (types.SystemdRunResult, error) {
+	cmd := exec.CommandContext(ctx, cmdParts[0], cmdParts[1:]...)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return types.SystemdRunResult{}, fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return types.SystemdRunResult{}, fmt.Errorf("failed to get stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return types.SystemdRunResult{}, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return types.SystemdRunResult{}, err
+	}
+	defer conn.Close()
+
+	properties = append(properties,
+		dbus.PropDescription(fmt.Sprintf("Sirberus: %s", strings.Join(cmdParts, " "))),
+		dbus.PropPids(uint32(cmd.Process.Pid)),
+	)
+
+	ch := make(chan string, 1)
+	_, err = conn.StartTransientUnitContext(ctx, name, jobModeFail, properties, ch)
+	if err == nil {
+		select {
+		case result := <-ch:
+			if result != jobResultDone {
+				err = fmt.Errorf("job result was %s", result)
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return types.SystemdRunResult{}, fmt.Errorf("failed to start transient scope %s: %w", name, err)
+	}
+
+	var streaming sync.WaitGroup
+	streaming.Add(2)
+	go func() {
+		defer streaming.Done()
+		scanOutput(ctx, stdout, outputCh)
+	}()
+	go func() {
+		defer streaming.Done()
+		scanOutput(ctx, stderr, outputCh)
+	}()
+	streaming.Wait()
+
+	result := types.SystemdRunResult{Unit: name, Result: "success", Code: "exited"}
+	if err := cmd.Wait(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return types.SystemdRunResult{}, fmt.Errorf("failed to wait for command: %w", err)
+		}
+
+		status := exitErr.Sys().(syscall.WaitStatus)
+		if status.Signaled() {
+			result.Result = "signal"
+			result.Code = "killed"
+			result.Status = int32(status.Signal())
+		} else {
+			result.Result = "exit-code"
+			result.Status = int32(status.ExitStatus())
+		}
+	}
+
+	return result, nil
+}
+
+// forwardLogs forwards journal output of a transient unit until the log stream ends
+func (s *SystemdService) forwardLogs(ctx context.Context, logCh <-chan types.LogEntry, logErrCh <-chan error, outputCh chan<- string) {
+	for {
+		select {
+		case entry, ok := <-logCh:
+			if !ok {
+				return
+			}
+			select {
+			case outputCh <- entry.Message:
+			case <-ctx.Done():
+				return
+			}
+		case err, ok := <-logErrCh:
+			if !ok {
+				logErrCh = nil
+				continue
+			}
+			s.logger.Warn("error streaming transient unit logs", "error", err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scanOutput sends each line read from r to outputCh
+func scanOutput(ctx context.Context, r io.Reader, outputCh chan<- string) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		select {
+		case outputCh <- scanner.Text():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// transientUnitName generates a unique name for a transient service or scope
+func transientUnitName(scope bool) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate unit name: %w", err)
+	}
+
+	unitType := "service"
+	if scope {
+		unitType = "scope"
+	}
+	return fmt.Sprintf("%s%s.%s", transientUnitPrefix, hex.EncodeToString(suffix), unitType), nil
+}
+
+// transientResourceProperties converts the resource limits of a run request to unit properties
+func transientResourceProperties(req types.SystemdRunRequest) ([]dbus.Property, error) {
+	properties := []dbus.Property{}
+
+	limits := []struct{ name, value string }{
+		{resourceMemoryMax, req.MemoryMax},
+		{resourceCPUQuota, req.CPUQuota},
+	}
+	for _, limit := range limits {
+		if limit.value == "" {
+			continue
+		}
+		property, err := resourceProperty(limit.name, limit.value)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, property)
+	}
+
+	if req.RuntimeMaxSec > math.MaxUint64/microsecondsPerSecond {
+		return nil, fmt.Errorf("%w: RuntimeMaxSec %d is too large", ErrInvalidProperty, req.RuntimeMaxSec)
+	}
+	if req.RuntimeMaxSec > 0 {
+		properties = append(properties, dbus.Property{Name: "RuntimeMaxUSec", Value: godbus.MakeVariant(req.RuntimeMaxSec * microsecondsPerSecond)})
+	}
+
+	return properties, nil
+}
+
+// exitCodeName converts a SIGCHLD code as reported in ExecMainCode to its name
+func exitCodeName(code int32) string {
+	switch code {
+	case 1:
+		return "exited"
+	case 2:
+		return "killed"
+	case 3:
+		return "dumped"
+	default:
+		return ""
+	}
+}
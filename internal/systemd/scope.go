@@ -0,0 +1,109 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	godbus "github.com/godbus/dbus/v5"
+)
+
+// ErrInvalidScope is returned for scope selectors that do not name a systemd manager
+var ErrInvalidScope = errors.New("invalid scope")
+
+// Scope selects the systemd manager a SystemdService talks to.
+// The zero value is the system manager.
+type Scope struct {
+	// User selects the per-user manager of UID instead of the system manager
+	User bool
+	// UID of the user manager
+	UID uint32
+}
+
+// ParseScope parses the "system" or "user" scope selector of a request. User scopes
+// default to the UID Sirberus runs as, so its own manager is used when uid is empty.
+func ParseScope(scope, uid string) (Scope, error) {
+	switch scope {
+	case "", scopeSystem:
+		if uid != "" {
+			return Scope{}, fmt.Errorf("%w: uid is only supported for the user scope", ErrInvalidScope)
+		}
+		return Scope{}, nil
+	case scopeUser:
+		if uid == "" {
+			return Scope{User: true, UID: uint32(os.Getuid())}, nil
+		}
+		id, err := strconv.ParseUint(uid, 10, 32)
+		if err != nil {
+			return Scope{}, fmt.Errorf("%w: uid %q", ErrInvalidScope, uid)
+		}
+		return Scope{User: true, UID: uint32(id)}, nil
+	}
+	return Scope{}, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+}
+
+func (s Scope) String() string {
+	if !s.User {
+		return scopeSystem
+	}
+	return fmt.Sprintf("%s:%d", scopeUser, s.UID)
+}
+
+// ForScope returns the service for the manager selected by scope. Services are cached
+// per scope so that every manager keeps its own job tracking.
+func (s *SystemdService) ForScope(scope Scope) *SystemdService {
+	if scope == s.scope {
+		return s
+	}
+
+	s.scopesMu.Lock()
+	defer s.scopesMu.Unlock()
+
+	if s.scopes == nil {
+		s.scopes = make(map[Scope]*SystemdService)
+	}
+	if service, ok := s.scopes[scope]; ok {
+		return service
+	}
+
+	service := &SystemdService{
+		logger: s.logger.With("scope", scope.String()),
+		scope:  scope,
+		jobs:   make(map[uint32]*trackedJob),
+	}
+	s.scopes[scope] = service
+	return service
+}
+
+// dialBus opens a raw connection to the bus of the manager, for calls go-systemd has no binding for
+func (s *SystemdService) dialBus(ctx context.Context) (*godbus.Conn, error) {
+	if s.scope.User {
+		return dialUserBus(ctx, s.scope.UID)
+	}
+	return godbus.ConnectSystemBus(godbus.WithContext(ctx))
+}
+
+// dialUserBus connects to the bus of a user manager through its /run/user/<uid>/bus socket.
+// The bus only accepts its owner and root, so Sirberus has to run as one of them.
+func dialUserBus(ctx context.Context, uid uint32) (*godbus.Conn, error) {
+	return godbus.Connect(fmt.Sprintf("unix:path=%s", userBusPath(uid)), godbus.WithContext(ctx))
+}
+
+// userBusPath returns the path of the bus socket of a user manager
+func userBusPath(uid uint32) string {
+	return fmt.Sprintf(userBusPathFormat, uid)
+}
+
+// journalUnitArgs returns the journalctl arguments matching the messages of a unit in this scope
+func (s *SystemdService) journalUnitArgs(unit string) []string {
+	if !s.scope.User {
+		return []string{"--unit", unit}
+	}
+	if s.scope.UID == uint32(os.Getuid()) {
+		return []string{"--user-unit", unit}
+	}
+	// --user-unit only matches messages of the calling user, so match the other user directly
+	return []string{"_SYSTEMD_USER_UNIT=" + unit, fmt.Sprintf("_UID=%d", s.scope.UID)}
+}
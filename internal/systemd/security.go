@@ -0,0 +1,471 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// ErrNotService is returned when a security assessment is requested for a unit that is not a service
+var ErrNotService = errors.New("unit is not a service")
+
+// securityCheck assesses one hardening setting of a service like a line of systemd-analyze security
+type securityCheck struct {
+	name string
+	// weight of the check in the exposure score
+	weight int
+	// maxBadness is the badness of a completely unhardened setting
+	maxBadness int
+	// assess returns the badness from 0 (hardened) to maxBadness and describes the current value
+	assess func(props map[string]interface{}) (badness int, description string)
+}
+
+// Linux capability numbers, see capability.h
+const (
+	capChown = iota
+	capDacOverride
+	capDacReadSearch
+	capFowner
+	capFsetid
+	capKill
+	capSetgid
+	capSetuid
+	capSetpcap
+	capLinuxImmutable
+	capNetBindService
+	capNetBroadcast
+	capNetAdmin
+	capNetRaw
+	capIpcLock
+	capIpcOwner
+	capSysModule
+	capSysRawio
+	capSysChroot
+	capSysPtrace
+	capSysPacct
+	capSysAdmin
+	capSysBoot
+	capSysNice
+	capSysResource
+	capSysTime
+	capSysTtyConfig
+	capMknod
+	capLease
+	capAuditWrite
+	capAuditControl
+	capSetfcap
+	capMacOverride
+	capMacAdmin
+	capSyslog
+	capWakeAlarm
+	capBlockSuspend
+	capAuditRead
+	capPerfmon
+	capBpf
+)
+
+// Namespace flags of RestrictNamespaces, see sched.h
+const (
+	cloneNewNS     = 0x00020000
+	cloneNewCgroup = 0x02000000
+	cloneNewUTS    = 0x04000000
+	cloneNewIPC    = 0x08000000
+	cloneNewUser   = 0x10000000
+	cloneNewPID    = 0x20000000
+	cloneNewNet    = 0x40000000
+)
+
+// commonAddressFamilies are the address families assessed by their own checks
+var commonAddressFamilies = []string{"AF_INET", "AF_INET6", "AF_UNIX", "AF_NETLINK", "AF_PACKET"}
+
+// securityChecks lists the assessed settings with weights following systemd-analyze security
+var securityChecks = []securityCheck{
+	{name: "User=/DynamicUser=", weight: 2000, maxBadness: 10, assess: assessUser},
+	boolSecurityCheck("NoNewPrivileges", 1000,
+		"Service processes cannot acquire new privileges",
+		"Service processes may acquire new privileges"),
+	boolSecurityCheck("PrivateTmp", 1000,
+		"Service has no access to other software's temporary files",
+		"Service has access to other software's temporary files"),
+	boolSecurityCheck("PrivateDevices", 1000,
+		"Service has no access to hardware devices",
+		"Service potentially has access to hardware devices"),
+	boolSecurityCheck("PrivateNetwork", 2500,
+		"Service has no access to the host's network",
+		"Service has access to the host's network"),
+	boolSecurityCheck("PrivateUsers", 1500,
+		"Service does not have access to other users",
+		"Service has access to other users"),
+	{name: "ProtectSystem=", weight: 1000, maxBadness: 10, assess: assessProtectSystem},
+	{name: "ProtectHome=", weight: 1000, maxBadness: 10, assess: assessProtectHome},
+	{name: "ProtectProc=", weight: 1000, maxBadness: 3, assess: assessProtectProc},
+	boolSecurityCheck("ProtectKernelTunables", 1000,
+		"Service cannot alter kernel tunables (/proc/sys, …)",
+		"Service may alter kernel tunables"),
+	boolSecurityCheck("ProtectKernelModules", 1000,
+		"Service cannot load or read kernel modules",
+		"Service may load or read kernel modules"),
+	boolSecurityCheck("ProtectKernelLogs", 1000,
+		"Service cannot read from or write to the kernel log ring buffer",
+		"Service may read from or write to the kernel log ring buffer"),
+	boolSecurityCheck("ProtectControlGroups", 1000,
+		"Service cannot modify the control group file system",
+		"Service may modify the control group file system"),
+	boolSecurityCheck("ProtectClock", 1000,
+		"Service cannot write to the hardware clock or system clock",
+		"Service may write to the hardware clock or system clock"),
+	boolSecurityCheck("ProtectHostname", 50,
+		"Service cannot change system host/domainname",
+		"Service may change system host/domainname"),
+	boolSecurityCheck("RestrictSUIDSGID", 1000,
+		"SUID/SGID file creation by service is restricted",
+		"Service may create SUID/SGID files"),
+	boolSecurityCheck("RestrictRealtime", 500,
+		"Service realtime scheduling access is restricted",
+		"Service may acquire realtime scheduling"),
+	boolSecurityCheck("LockPersonality", 100,
+		"Service cannot change ABI personality",
+		"Service may change ABI personality"),
+	boolSecurityCheck("MemoryDenyWriteExecute", 100,
+		"Service cannot create writable executable memory mappings",
+		"Service may create writable executable memory mappings"),
+	boolSecurityCheck("RemoveIPC", 100,
+		"Service user cannot leave SysV IPC objects around",
+		"Service user may leave SysV IPC objects around"),
+	{name: "SystemCallArchitectures=", weight: 1000, maxBadness: 10, assess: assessSystemCallArchitectures},
+	{name: "SystemCallFilter=", weight: 1000, maxBadness: 10, assess: assessSystemCallFilter},
+	addressFamilyCheck("RestrictAddressFamilies=~AF_(INET|INET6)", 1500, "Internet", "AF_INET", "AF_INET6"),
+	addressFamilyCheck("RestrictAddressFamilies=~AF_UNIX", 25, "local", "AF_UNIX"),
+	addressFamilyCheck("RestrictAddressFamilies=~AF_NETLINK", 200, "Netlink", "AF_NETLINK"),
+	addressFamilyCheck("RestrictAddressFamilies=~AF_PACKET", 1000, "packet", "AF_PACKET"),
+	{name: "RestrictAddressFamilies=~…", weight: 1250, maxBadness: 1, assess: assessOtherAddressFamilies},
+	namespaceCheck("cgroup", cloneNewCgroup),
+	namespaceCheck("ipc", cloneNewIPC),
+	namespaceCheck("net", cloneNewNet),
+	namespaceCheck("mnt", cloneNewNS),
+	namespaceCheck("pid", cloneNewPID),
+	namespaceCheck("user", cloneNewUser),
+	namespaceCheck("uts", cloneNewUTS),
+	capabilityCheck("CAP_SYS_ADMIN", 1500, "administer the system", capSysAdmin),
+	capabilityCheck("CAP_SET(UID|GID|PCAP)", 1500, "change UID/GID identities and capabilities", capSetuid, capSetgid, capSetpcap),
+	capabilityCheck("CAP_SYS_PTRACE", 1500, "trace other processes", capSysPtrace),
+	capabilityCheck("CAP_SYS_MODULE", 1500, "load kernel modules", capSysModule),
+	capabilityCheck("CAP_SYS_RAWIO", 1500, "issue raw I/O", capSysRawio),
+	capabilityCheck("CAP_NET_ADMIN", 1500, "administer network configuration", capNetAdmin),
+	capabilityCheck("CAP_SYSLOG", 1500, "access the kernel log", capSyslog),
+	capabilityCheck("CAP_MAC_*", 1500, "override or administer mandatory access control", capMacOverride, capMacAdmin),
+	capabilityCheck("CAP_(CHOWN|FSETID|SETFCAP)", 1500, "change file ownership, access mode and capabilities", capChown, capFsetid, capSetfcap),
+	capabilityCheck("CAP_(DAC_*|FOWNER|IPC_OWNER)", 1500, "override UNIX file and IPC permission checks", capDacOverride, capDacReadSearch, capFowner, capIpcOwner),
+	capabilityCheck("CAP_BPF", 1500, "load BPF programs", capBpf),
+	capabilityCheck("CAP_SYS_TIME", 1000, "change the system clock", capSysTime),
+	capabilityCheck("CAP_LINUX_IMMUTABLE", 1000, "mark files immutable", capLinuxImmutable),
+	capabilityCheck("CAP_AUDIT_*", 500, "read and write the audit log", capAuditWrite, capAuditControl, capAuditRead),
+	capabilityCheck("CAP_SYS_(NICE|RESOURCE)", 500, "raise process priorities and resource limits", capSysNice, capSysResource),
+	capabilityCheck("CAP_MKNOD", 500, "create device nodes", capMknod),
+	capabilityCheck("CAP_KILL", 500, "send signals to arbitrary processes", capKill),
+	capabilityCheck("CAP_NET_(BIND_SERVICE|BROADCAST|RAW)", 500, "bind to privileged ports and use raw sockets", capNetBindService, capNetBroadcast, capNetRaw),
+	capabilityCheck("CAP_IPC_LOCK", 500, "lock memory", capIpcLock),
+	capabilityCheck("CAP_PERFMON", 500, "use performance monitoring", capPerfmon),
+	capabilityCheck("CAP_SYS_BOOT", 100, "reboot the system", capSysBoot),
+	capabilityCheck("CAP_SYS_CHROOT", 100, "issue chroot()", capSysChroot),
+	capabilityCheck("CAP_(LEASE|BLOCK_SUSPEND|WAKE_ALARM|SYS_PACCT)", 25, "take leases, block suspend, set wake alarms and configure accounting", capLease, capBlockSuspend, capWakeAlarm, capSysPacct),
+	capabilityCheck("CAP_SYS_TTY_CONFIG", 1, "configure terminals", capSysTtyConfig),
+}
+
+// securityRatings maps the minimum exposure, from 0 to 100, to the rating systemd-analyze prints
+var securityRatings = []struct {
+	exposure int
+	rating   string
+}{
+	{100, "DANGEROUS"},
+	{90, "UNSAFE"},
+	{75, "EXPOSED"},
+	{50, "MEDIUM"},
+	{1, "OK"},
+	{0, "PERFECT"},
+}
+
+// GetServiceSecurity assesses the sandboxing of a service like systemd-analyze security
+func (s *SystemdService) GetServiceSecurity(name string) (*types.SystemdSecurityAssessment, error) {
+	if UnitType(name) != "service" {
+		return nil, fmt.Errorf("%w: %s", ErrNotService, name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	props, err := conn.GetAllPropertiesContext(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service properties: %w", err)
+	}
+	if getStringProperty(props, "LoadState") == "not-found" {
+		return nil, fmt.Errorf("unit %s not found", name)
+	}
+
+	return assessSecurity(name, props), nil
+}
+
+// GetSecurityReport assesses the sandboxing of every loaded service, most exposed first
+func (s *SystemdService) GetSecurityReport() (*types.SystemdSecurityReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), securityReportTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	units, err := conn.ListUnitsByPatternsContext(ctx, []string{}, []string{"*.service"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	report := &types.SystemdSecurityReport{Services: []types.SystemdSecuritySummary{}}
+	for _, unit := range units {
+		if unit.LoadState != "loaded" {
+			continue
+		}
+		props, err := conn.GetAllPropertiesContext(ctx, unit.Name)
+		if err != nil {
+			s.logger.Warn("failed to get service properties",
+				"service", unit.Name,
+				"error", err)
+			continue
+		}
+
+		assessment := assessSecurity(unit.Name, props)
+		report.Services = append(report.Services, types.SystemdSecuritySummary{
+			Unit:        unit.Name,
+			ActiveState: unit.ActiveState,
+			Exposure:    assessment.Exposure,
+			Rating:      assessment.Rating,
+		})
+	}
+
+	sort.Slice(report.Services, func(i, j int) bool {
+		if report.Services[i].Exposure != report.Services[j].Exposure {
+			return report.Services[i].Exposure > report.Services[j].Exposure
+		}
+		return report.Services[i].Unit < report.Services[j].Unit
+	})
+	report.Count = len(report.Services)
+
+	return report, nil
+}
+
+// assessSecurity runs every security check against the properties of a service and
+// combines their weighted badness into an exposure from 0.0 to 10.0
+func assessSecurity(name string, props map[string]interface{}) *types.SystemdSecurityAssessment {
+	assessment := &types.SystemdSecurityAssessment{
+		Unit:   name,
+		Checks: make([]types.SystemdSecurityCheck, 0, len(securityChecks)),
+	}
+
+	var weightSum, badnessSum float64
+	for _, check := range securityChecks {
+		badness, description := check.assess(props)
+		exposure := float64(badness) / float64(check.maxBadness)
+
+		weightSum += float64(check.weight)
+		badnessSum += exposure * float64(check.weight)
+
+		assessment.Checks = append(assessment.Checks, types.SystemdSecurityCheck{
+			Name:        check.name,
+			Description: description,
+			Passed:      badness == 0,
+			Weight:      check.weight,
+			Exposure:    math.Round(exposure*100) / 100,
+		})
+	}
+
+	// Most exposing checks first, like systemd-analyze security
+	sort.SliceStable(assessment.Checks, func(i, j int) bool {
+		a, b := assessment.Checks[i], assessment.Checks[j]
+		return a.Exposure*float64(a.Weight) > b.Exposure*float64(b.Weight)
+	})
+
+	exposure := int(math.Round(badnessSum * 100 / weightSum))
+	assessment.Exposure = float64(exposure) / 10
+	for _, rating := range securityRatings {
+		if exposure >= rating.exposure {
+			assessment.Rating = rating.rating
+			break
+		}
+	}
+
+	return assessment
+}
+
+// boolSecurityCheck returns a check passing if a boolean property is enabled
+func boolSecurityCheck(property string, weight int, good, bad string) securityCheck {
+	return securityCheck{
+		name:       property + "=",
+		weight:     weight,
+		maxBadness: 1,
+		assess: func(props map[string]interface{}) (int, string) {
+			if getBoolProperty(props, property) {
+				return 0, good
+			}
+			return 1, bad
+		},
+	}
+}
+
+// capabilityCheck returns a check passing if none of the capabilities are in the bounding set
+func capabilityCheck(name string, weight int, action string, capabilities ...uint) securityCheck {
+	var mask uint64
+	for _, capability := range capabilities {
+		mask |= 1 << capability
+	}
+	return securityCheck{
+		name:       "CapabilityBoundingSet=~" + name,
+		weight:     weight,
+		maxBadness: 1,
+		assess: func(props map[string]interface{}) (int, string) {
+			if getUint64Property(props, "CapabilityBoundingSet")&mask == 0 {
+				return 0, "Service cannot " + action
+			}
+			return 1, "Service may " + action
+		},
+	}
+}
+
+// namespaceCheck returns a check passing if a namespace type may not be created
+func namespaceCheck(namespace string, flag uint64) securityCheck {
+	return securityCheck{
+		name:       "RestrictNamespaces=~" + namespace,
+		weight:     500,
+		maxBadness: 1,
+		assess: func(props map[string]interface{}) (int, string) {
+			// RestrictNamespaces holds the namespace types that may still be created
+			if getUint64Property(props, "RestrictNamespaces")&flag == 0 {
+				return 0, fmt.Sprintf("Service cannot create %s namespaces", namespace)
+			}
+			return 1, fmt.Sprintf("Service may create %s namespaces", namespace)
+		},
+	}
+}
+
+// addressFamilyCheck returns a check passing if none of the address families may be used
+func addressFamilyCheck(name string, weight int, kind string, families ...string) securityCheck {
+	return securityCheck{
+		name:       name,
+		weight:     weight,
+		maxBadness: 1,
+		assess: func(props map[string]interface{}) (int, string) {
+			allowList, list := getAllowListProperty(props, "RestrictAddressFamilies")
+			for _, family := range families {
+				if slices.Contains(list, family) == allowList {
+					return 1, fmt.Sprintf("Service may allocate %s sockets", kind)
+				}
+			}
+			return 0, fmt.Sprintf("Service cannot allocate %s sockets", kind)
+		},
+	}
+}
+
+func assessOtherAddressFamilies(props map[string]interface{}) (int, string) {
+	allowList, list := getAllowListProperty(props, "RestrictAddressFamilies")
+	if allowList && !slices.ContainsFunc(list, func(family string) bool {
+		return !slices.Contains(commonAddressFamilies, family)
+	}) {
+		return 0, "Service cannot allocate exotic sockets"
+	}
+	return 1, "Service may allocate exotic sockets"
+}
+
+func assessUser(props map[string]interface{}) (int, string) {
+	if getBoolProperty(props, "DynamicUser") {
+		return 0, "Service runs under a transient non-root user identity"
+	}
+	switch getStringProperty(props, "User") {
+	case "", "root", "0":
+		return 10, "Service runs as root user"
+	case "nobody", "65534":
+		return 9, "Service runs under the shared nobody user identity"
+	}
+	return 0, "Service runs under a static non-root user identity"
+}
+
+func assessProtectSystem(props map[string]interface{}) (int, string) {
+	switch getStringProperty(props, "ProtectSystem") {
+	case "strict":
+		return 0, "Service has strict read-only access to the OS file hierarchy"
+	case "full":
+		return 3, "Service has very limited write access to the OS file hierarchy"
+	case "yes":
+		return 5, "Service has limited write access to the OS file hierarchy"
+	}
+	return 10, "Service has full access to the OS file hierarchy"
+}
+
+func assessProtectHome(props map[string]interface{}) (int, string) {
+	switch getStringProperty(props, "ProtectHome") {
+	case "yes":
+		return 0, "Service has no access to home directories"
+	case "tmpfs":
+		return 1, "Service has no access to home directories, which are replaced by an empty tmpfs"
+	case "read-only":
+		return 5, "Service has read-only access to home directories"
+	}
+	return 10, "Service has full access to home directories"
+}
+
+func assessProtectProc(props map[string]interface{}) (int, string) {
+	switch getStringProperty(props, "ProtectProc") {
+	case "noaccess":
+		return 0, "Service has no access to other processes' /proc/ entries"
+	case "invisible":
+		return 1, "Service cannot see other processes in /proc/"
+	case "ptraceable":
+		return 2, "Service can only see processes it may trace in /proc/"
+	}
+	return 3, "Service has full access to process tree (/proc/)"
+}
+
+func assessSystemCallArchitectures(props map[string]interface{}) (int, string) {
+	architectures := getStringArrayProperty(props, "SystemCallArchitectures")
+	switch {
+	case len(architectures) == 0:
+		return 10, "Service may execute system calls with all ABIs"
+	case len(architectures) == 1 && architectures[0] == "native":
+		return 0, "Service may execute system calls only with native ABI"
+	}
+	return 3, fmt.Sprintf("Service may execute system calls with %s ABIs", strings.Join(architectures, ", "))
+}
+
+func assessSystemCallFilter(props map[string]interface{}) (int, string) {
+	allowList, list := getAllowListProperty(props, "SystemCallFilter")
+	switch {
+	case allowList:
+		return 0, "System call allow list defined for service"
+	case len(list) > 0:
+		return 5, "System call deny list defined for service"
+	}
+	return 10, "Service does not filter system calls"
+}
+
+// getAllowListProperty reads a property holding a flag whether the list is an allow list and
+// the list itself, such as RestrictAddressFamilies. An empty deny list restricts nothing.
+func getAllowListProperty(props map[string]interface{}, name string) (bool, []string) {
+	value, ok := props[name].([]interface{})
+	if !ok || len(value) != 2 {
+		return false, nil
+	}
+	allowList, _ := value[0].(bool)
+	list, _ := value[1].([]string)
+	return allowList, list
+}
@@ -5,18 +5,41 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Keyruu/sirberus/internal/types"
 	"github.com/coreos/go-systemd/v22/dbus"
+	godbus "github.com/godbus/dbus/v5"
 )
 
 type SystemdService struct {
 	logger *slog.Logger
+	// scope selects the system manager or a per-user manager
+	scope Scope
+	// scopes caches the services of other managers created by ForScope
+	scopesMu sync.Mutex
+	scopes   map[Scope]*SystemdService
+	// overrideMu serializes changes to unit files so verification and rollback don't interleave
+	overrideMu sync.Mutex
+	// jobs holds the jobs queued by unit operations until their results have expired
+	jobsMu sync.Mutex
+	jobs   map[uint32]*trackedJob
+	// descriptions caches the descriptions of unit files that are not loaded by path
+	descriptionsMu sync.Mutex
+	descriptions   map[string]cachedDescription
+}
+
+// cachedDescription is the description of a unit file when it was last modified at modTime
+type cachedDescription struct {
+	modTime     time.Time
+	description string
 }
 
 func NewSystemdService(logger *slog.Logger) (*SystemdService, error) {
@@ -26,11 +49,20 @@ func NewSystemdService(logger *slog.Logger) (*SystemdService, error) {
 
 	return &SystemdService{
 		logger: logger.With("component", "systemd_service"),
+		jobs:   make(map[uint32]*trackedJob),
 	}, nil
 }
 
 func (s *SystemdService) newConnection(ctx context.Context) (*dbus.Conn, error) {
-	conn, err := dbus.NewWithContext(ctx)
+	var conn *dbus.Conn
+	var err error
+	if s.scope.User {
+		conn, err = dbus.NewConnection(func() (*godbus.Conn, error) {
+			return dialUserBus(ctx, s.scope.UID)
+		})
+	} else {
+		conn, err = dbus.NewWithContext(ctx)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to systemd: %w", err)
 	}
@@ -47,24 +79,17 @@ func (s *SystemdService) GetUnitDetails(name string) (*types.SystemdServiceDetai
 	}
 	defer conn.Close()
 
-	units, err := conn.ListUnitsContext(ctx)
+	// ListUnitsByNames loads the unit if needed, so installed units that are
+	// not currently loaded can still be inspected
+	units, err := conn.ListUnitsByNamesContext(ctx, []string{name})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list units: %w", err)
 	}
 
-	var unit dbus.UnitStatus
-	found := false
-	for _, u := range units {
-		if u.Name == name {
-			unit = u
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return nil, fmt.Errorf("service %s not found", name)
+	if len(units) == 0 || (units[0].LoadState == "not-found" && units[0].ActiveState == "inactive") {
+		return nil, fmt.Errorf("unit %s not found", name)
 	}
+	unit := units[0]
 
 	metrics := &ServiceMetrics{
 		CPUUsage:    0,
@@ -157,17 +182,20 @@ func (s *SystemdService) GetUnitDetails(name string) (*types.SystemdServiceDetai
 
 	cgroup := getStringProperty(props, "ControlGroup")
 	fragmentPath := getStringProperty(props, "FragmentPath")
+	unitFileState := getStringProperty(props, "UnitFileState")
 
 	details := &types.SystemdServiceDetails{
 		Service: types.SystemdService{
-			Name:        unit.Name,
-			Description: unit.Description,
-			LoadState:   unit.LoadState,
-			ActiveState: unit.ActiveState,
-			SubState:    unit.SubState,
-			CPUUsage:    metrics.CPUUsage,
-			MemoryUsage: metrics.MemoryUsage,
-			Uptime:      uptimeSeconds,
+			Name:          unit.Name,
+			Description:   unit.Description,
+			Type:          UnitType(unit.Name),
+			LoadState:     unit.LoadState,
+			ActiveState:   unit.ActiveState,
+			SubState:      unit.SubState,
+			CPUUsage:      metrics.CPUUsage,
+			MemoryUsage:   metrics.MemoryUsage,
+			Uptime:        uptimeSeconds,
+			UnitFileState: unitFileState,
 		},
 		DropIn:         dropInPaths,
 		Since:          since,
@@ -186,12 +214,19 @@ func (s *SystemdService) GetUnitDetails(name string) (*types.SystemdServiceDetai
 		CPUTimeNSec:    cpuTime,
 		CGroup:         cgroup,
 		FragmentPath:   fragmentPath,
+		Limits:         getResourceLimits(props),
 	}
+	if details.Service.Type == "service" {
+		details.Security = assessSecurity(unit.Name, props)
+	}
+	// Only units whose last run did not succeed cost a journal query for their failure logs
+	details.SystemdFailureInfo = s.getFailureInfo(ctx, unit.Name, props)
 
 	return details, nil
 }
 
-func (s *SystemdService) ListUnits() (*types.SystemdServiceList, error) {
+// ListUnits returns all loaded units of the given type, or of every type if unitType is empty
+func (s *SystemdService) ListUnits(unitType string) (*types.SystemdServiceList, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
 	defer cancel()
 
@@ -201,16 +236,26 @@ func (s *SystemdService) ListUnits() (*types.SystemdServiceList, error) {
 	}
 	defer conn.Close()
 
-	units, err := conn.ListUnitsContext(ctx)
+	patterns := []string{}
+	if unitType != "" {
+		patterns = append(patterns, "*."+unitType)
+	}
+
+	units, err := conn.ListUnitsByPatternsContext(ctx, []string{}, patterns)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list units: %w", err)
 	}
 
+	unitFiles := s.listUnitFiles(ctx, conn, patterns)
+	fileStates := make(map[string]string, len(unitFiles))
+	for _, f := range unitFiles {
+		fileStates[filepath.Base(f.Path)] = f.Type
+	}
+
+	loaded := make(map[string]bool, len(units))
 	services := make([]types.SystemdService, 0, len(units))
 	for _, unit := range units {
-		if !strings.HasSuffix(unit.Name, ".service") {
-			continue
-		}
+		loaded[unit.Name] = true
 
 		metrics := &ServiceMetrics{
 			CPUUsage:    0,
@@ -241,25 +286,130 @@ func (s *SystemdService) ListUnits() (*types.SystemdServiceList, error) {
 		}
 
 		service := types.SystemdService{
-			Name:        unit.Name,
-			Description: unit.Description,
-			LoadState:   unit.LoadState,
-			ActiveState: unit.ActiveState,
-			SubState:    unit.SubState,
-			CPUUsage:    metrics.CPUUsage,
-			MemoryUsage: metrics.MemoryUsage,
-			Uptime:      uptimeSeconds,
+			Name:          unit.Name,
+			Description:   unit.Description,
+			Type:          UnitType(unit.Name),
+			LoadState:     unit.LoadState,
+			ActiveState:   unit.ActiveState,
+			SubState:      unit.SubState,
+			CPUUsage:      metrics.CPUUsage,
+			MemoryUsage:   metrics.MemoryUsage,
+			Uptime:        uptimeSeconds,
+			UnitFileState: lookupUnitFileState(fileStates, unit.Name),
 		}
 
 		services = append(services, service)
 	}
 
+	// Add installed unit files that systemd has not loaded, such as disabled services
+	for _, f := range unitFiles {
+		name := filepath.Base(f.Path)
+		if loaded[name] || !isStandaloneUnitFile(name, f.Type) {
+			continue
+		}
+		loaded[name] = true
+
+		services = append(services, types.SystemdService{
+			Name:          name,
+			Description:   s.unitDescription(f.Path),
+			Type:          UnitType(name),
+			LoadState:     loadStateNotLoaded,
+			ActiveState:   "inactive",
+			SubState:      "dead",
+			UnitFileState: f.Type,
+		})
+	}
+
 	return &types.SystemdServiceList{
 		Services: services,
 		Count:    len(services),
 	}, nil
 }
 
+// listUnitFiles returns the installed unit files matching the patterns, logging and
+// returning an empty list on failure so that loaded units can still be listed
+func (s *SystemdService) listUnitFiles(ctx context.Context, conn *dbus.Conn, patterns []string) []dbus.UnitFile {
+	files, err := conn.ListUnitFilesByPatternsContext(ctx, []string{}, patterns)
+	if err != nil {
+		s.logger.Error("failed to list unit files", "error", err)
+		return []dbus.UnitFile{}
+	}
+	return files
+}
+
+// isTemplateUnit reports whether name is a template unit such as getty@.service,
+// which cannot be loaded without an instance name
+func isTemplateUnit(name string) bool {
+	at := strings.Index(name, "@")
+	return at >= 0 && at == strings.LastIndex(name, ".")-1
+}
+
+// isStandaloneUnitFile reports whether a unit file defines a unit of its own that can be
+// listed. Templates cannot be loaded without an instance name, and aliases and linked files
+// only point at units listed under their own name.
+func isStandaloneUnitFile(name, state string) bool {
+	switch state {
+	case unitFileStateAlias, unitFileStateLinked, unitFileStateLinkedRuntime:
+		return false
+	}
+	return !isTemplateUnit(name)
+}
+
+// unitDescription returns the Description= setting of a unit file, reading the file only
+// if it changed since the description was cached
+func (s *SystemdService) unitDescription(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	s.descriptionsMu.Lock()
+	defer s.descriptionsMu.Unlock()
+
+	if cached, ok := s.descriptions[path]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.description
+	}
+	if s.descriptions == nil {
+		s.descriptions = make(map[string]cachedDescription)
+	}
+	description := readUnitDescription(path)
+	s.descriptions[path] = cachedDescription{modTime: info.ModTime(), description: description}
+	return description
+}
+
+// readUnitDescription reads the Description= setting from a unit file
+func readUnitDescription(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := strings.CutPrefix(line, "Description="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// lookupUnitFileState returns the unit file state for a unit, falling back
+// to the template unit file for instances such as getty@tty1.service
+func lookupUnitFileState(states map[string]string, name string) string {
+	if state, ok := states[name]; ok {
+		return state
+	}
+
+	if at := strings.Index(name, "@"); at >= 0 {
+		if dot := strings.LastIndex(name, "."); dot > at {
+			return states[name[:at+1]+name[dot:]]
+		}
+	}
+	return ""
+}
+
 func getStringProperty(props map[string]interface{}, name string) string {
 	if v, ok := props[name].(string); ok {
 		return v
@@ -274,6 +424,20 @@ func getStringArrayProperty(props map[string]interface{}, name string) []string
 	return []string{}
 }
 
+func getBoolProperty(props map[string]interface{}, name string) bool {
+	if v, ok := props[name].(bool); ok {
+		return v
+	}
+	return false
+}
+
+func getInt32Property(props map[string]interface{}, name string) int32 {
+	if v, ok := props[name].(int32); ok {
+		return v
+	}
+	return 0
+}
+
 func getUint32Property(props map[string]interface{}, name string) uint32 {
 	if v, ok := props[name].(uint32); ok {
 		return v
@@ -291,6 +455,15 @@ func getUint64Property(props map[string]interface{}, name string) uint64 {
 	return 0
 }
 
+// formatTimestamp formats a systemd timestamp in microseconds since the epoch as RFC3339,
+// returning an empty string for unset timestamps
+func formatTimestamp(usec uint64) string {
+	if usec == 0 || usec == math.MaxUint64 {
+		return ""
+	}
+	return time.UnixMicro(int64(usec)).Format(time.RFC3339)
+}
+
 // getPropertyNames returns a list of all property names in the map
 // This is useful for debugging when a property is not found
 func getPropertyNames(props map[string]interface{}) []string {
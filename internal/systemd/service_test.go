@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
 )
 
 // TestWithRealService runs a comprehensive test with a real systemd service
@@ -26,7 +28,7 @@ func TestWithRealService(t *testing.T) {
 
 	// Test listing units and finding our test service
 	t.Run("ListUnits", func(t *testing.T) {
-		list, err := s.ListUnits()
+		list, err := s.ListUnits("service")
 		if err != nil {
 			t.Fatalf("ListUnits failed: %v", err)
 		}
@@ -83,7 +85,7 @@ func TestWithRealService(t *testing.T) {
 
 	// Test stopping the service
 	t.Run("StopUnit", func(t *testing.T) {
-		err := s.StopUnit(testServiceName)
+		_, err := s.StopUnit(testServiceName, jobModeReplace)
 		if err != nil {
 			t.Fatalf("StopUnit failed: %v", err)
 		}
@@ -127,7 +129,7 @@ func TestWithRealService(t *testing.T) {
 
 	// Test starting the service
 	t.Run("StartUnit", func(t *testing.T) {
-		err := s.StartUnit(testServiceName)
+		_, err := s.StartUnit(testServiceName, jobModeReplace)
 		if err != nil {
 			t.Fatalf("StartUnit failed: %v", err)
 		}
@@ -154,7 +156,7 @@ func TestWithRealService(t *testing.T) {
 		initialInvocation := details.Invocation
 
 		// Restart the service
-		err = s.RestartUnit(testServiceName)
+		_, err = s.RestartUnit(testServiceName, jobModeReplace)
 		if err != nil {
 			t.Fatalf("RestartUnit failed: %v", err)
 		}
@@ -179,7 +181,7 @@ func TestWithRealService(t *testing.T) {
 	// Test streaming logs
 	t.Run("StreamServiceLogs", func(t *testing.T) {
 		// Make sure the service is running and generating logs
-		err := s.RestartUnit(testServiceName)
+		_, err := s.RestartUnit(testServiceName, jobModeReplace)
 		if err != nil {
 			t.Fatalf("Failed to restart service for log test: %v", err)
 		}
@@ -192,10 +194,10 @@ func TestWithRealService(t *testing.T) {
 		defer cancel()
 
 		// Stream logs without following
-		logCh, errCh := s.StreamServiceLogs(ctx, testServiceName, false, 5)
+		logCh, errCh := s.StreamServiceLogs(ctx, testServiceName, false, 5, types.LogFilter{})
 
 		// Collect logs
-		var logs []string
+		var logs []types.LogEntry
 		var streamErr error
 
 	collectLogs:
@@ -227,14 +229,14 @@ func TestWithRealService(t *testing.T) {
 		t.Logf("Found %d log entries", len(logs))
 		for i, log := range logs {
 			if i < 3 { // Only log a few entries
-				t.Logf("Log entry: %s", log)
+				t.Logf("Log entry: %+v", log)
 			}
 		}
 
 		// Check if we got any logs containing our test message
 		var foundTestLog bool
 		for _, log := range logs {
-			if strings.Contains(log, "Sirberus test service running") {
+			if strings.Contains(log.Message, "Sirberus test service running") {
 				foundTestLog = true
 				break
 			}
@@ -248,13 +250,13 @@ func TestWithRealService(t *testing.T) {
 		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		logCh, errCh = s.StreamServiceLogs(ctx, testServiceName, true, 1)
+		logCh, errCh = s.StreamServiceLogs(ctx, testServiceName, true, 1, types.LogFilter{})
 
 		// Just verify we can read from the channel
 		select {
 		case log, ok := <-logCh:
 			if ok {
-				t.Logf("Got log in follow mode: %s", log)
+				t.Logf("Got log in follow mode: %+v", log)
 			}
 		case err := <-errCh:
 			if err != nil && err != context.DeadlineExceeded && err != context.Canceled {
@@ -268,13 +270,13 @@ func TestWithRealService(t *testing.T) {
 		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
 
-		logCh, errCh = s.StreamServiceLogs(ctx, "nonexistent.service", false, 5)
+		logCh, errCh = s.StreamServiceLogs(ctx, "nonexistent.service", false, 5, types.LogFilter{})
 
 		// Handle various possible outcomes
 		select {
 		case log, ok := <-logCh:
 			if ok {
-				t.Logf("Unexpectedly received log for non-existent service: %s", log)
+				t.Logf("Unexpectedly received log for non-existent service: %+v", log)
 			} else {
 				t.Log("Log channel closed without error (valid behavior)")
 			}
@@ -294,7 +296,7 @@ func TestWithRealService(t *testing.T) {
 	// Test service metrics
 	t.Run("ServiceMetrics", func(t *testing.T) {
 		// Make sure the service is running
-		err := s.RestartUnit(testServiceName)
+		_, err := s.RestartUnit(testServiceName, jobModeReplace)
 		if err != nil {
 			t.Fatalf("Failed to restart service for metrics test: %v", err)
 		}
@@ -0,0 +1,102 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// ListTimers returns all loaded timers with their schedule and activated unit
+func (s *SystemdService) ListTimers() (*types.SystemdTimerList, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	units, err := conn.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	timers := make([]types.SystemdTimer, 0)
+	for _, unit := range units {
+		if UnitType(unit.Name) != "timer" {
+			continue
+		}
+
+		props, err := conn.GetUnitTypePropertiesContext(ctx, unit.Name, "Timer")
+		if err != nil {
+			s.logger.Error("failed to get timer properties",
+				"timer", unit.Name,
+				"error", err)
+			continue
+		}
+
+		timers = append(timers, types.SystemdTimer{
+			Name:        unit.Name,
+			Description: unit.Description,
+			ActiveState: unit.ActiveState,
+			Unit:        getStringProperty(props, "Unit"),
+			NextElapse:  formatTimestamp(getUint64Property(props, "NextElapseUSecRealtime")),
+			LastTrigger: formatTimestamp(getUint64Property(props, "LastTriggerUSec")),
+			OnCalendar:  getCalendarExpressions(props),
+			Persistent:  getBoolProperty(props, "Persistent"),
+		})
+	}
+
+	return &types.SystemdTimerList{
+		Timers: timers,
+		Count:  len(timers),
+	}, nil
+}
+
+// TriggerTimer starts the unit activated by a timer immediately without waiting
+// for the schedule and returns the queued start job of that unit
+func (s *SystemdService) TriggerTimer(name string) (*types.SystemdJob, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	props, err := conn.GetUnitTypePropertiesContext(ctx, name, "Timer")
+	conn.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timer properties: %w", err)
+	}
+
+	unit := getStringProperty(props, "Unit")
+	if unit == "" {
+		return nil, fmt.Errorf("timer %s not found", name)
+	}
+
+	return s.StartUnit(unit, jobModeReplace)
+}
+
+// getCalendarExpressions extracts the OnCalendar expressions from the TimersCalendar
+// property, which systemd exposes as an array of (base, expression, next elapse) tuples
+func getCalendarExpressions(props map[string]interface{}) []string {
+	expressions := []string{}
+
+	entries, ok := props["TimersCalendar"].([][]interface{})
+	if !ok {
+		return expressions
+	}
+
+	for _, entry := range entries {
+		if len(entry) < 2 {
+			continue
+		}
+		if expression, ok := entry[1].(string); ok {
+			expressions = append(expressions, expression)
+		}
+	}
+	return expressions
+}
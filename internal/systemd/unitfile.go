@@ -0,0 +1,84 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+// GetUnitFile returns the main unit file and its drop-ins in the order systemd applies them
+func (s *SystemdService) GetUnitFile(name string) (*types.SystemdUnitFile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	props, err := conn.GetUnitPropertiesContext(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unit properties: %w", err)
+	}
+
+	fragmentPath := getStringProperty(props, "FragmentPath")
+	dropInPaths := getStringArrayProperty(props, "DropInPaths")
+	if fragmentPath == "" && len(dropInPaths) == 0 {
+		return nil, fmt.Errorf("unit file for %s not found", name)
+	}
+
+	files := make([]types.SystemdUnitFileSource, 0, len(dropInPaths)+1)
+	if fragmentPath != "" {
+		file, err := readUnitFileSource(fragmentPath, false)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	for _, path := range dropInPaths {
+		file, err := readUnitFileSource(path, true)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return &types.SystemdUnitFile{
+		Name:  name,
+		Files: files,
+	}, nil
+}
+
+func readUnitFileSource(path string, dropIn bool) (types.SystemdUnitFileSource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return types.SystemdUnitFileSource{}, fmt.Errorf("failed to read unit file %s: %w", path, err)
+	}
+
+	return types.SystemdUnitFileSource{
+		Path:    path,
+		Origin:  unitFileOrigin(path),
+		DropIn:  dropIn,
+		Content: string(content),
+	}, nil
+}
+
+// unitFileOrigin classifies a unit file path as vendor-supplied, admin override or runtime file
+func unitFileOrigin(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/etc/"):
+		return unitFileOriginAdmin
+	case strings.HasPrefix(path, "/run/"):
+		return unitFileOriginRuntime
+	case strings.HasPrefix(path, "/usr/lib/"), strings.HasPrefix(path, "/lib/"),
+		strings.HasPrefix(path, "/usr/local/lib/"):
+		return unitFileOriginVendor
+	default:
+		return unitFileOriginOther
+	}
+}
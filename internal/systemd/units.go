@@ -0,0 +1,43 @@
+package systemd
+
+import "strings"
+
+// unitTypes lists the unit types managed by systemd
+var unitTypes = []string{
+	"service",
+	"socket",
+	"target",
+	"device",
+	"mount",
+	"automount",
+	"swap",
+	"timer",
+	"path",
+	"slice",
+	"scope",
+}
+
+// IsValidUnitType reports whether unitType is a known systemd unit type
+func IsValidUnitType(unitType string) bool {
+	for _, t := range unitTypes {
+		if t == unitType {
+			return true
+		}
+	}
+	return false
+}
+
+// UnitType returns the type of a unit derived from its suffix,
+// or an empty string if the name has no known unit type suffix
+func UnitType(name string) string {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return ""
+	}
+
+	unitType := name[dot+1:]
+	if !IsValidUnitType(unitType) {
+		return ""
+	}
+	return unitType
+}
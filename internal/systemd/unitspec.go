@@ -0,0 +1,383 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Keyruu/sirberus/internal/types"
+)
+
+var (
+	// ErrInvalidUnitSpec is returned for unit specs that cannot be rendered to a unit file
+	ErrInvalidUnitSpec = errors.New("invalid unit spec")
+	// ErrUnitExists is returned when creating a unit whose file already exists
+	ErrUnitExists = errors.New("unit already exists")
+	// ErrUnitNotManaged is returned when deleting a unit that was not created by Sirberus
+	ErrUnitNotManaged = errors.New("unit is not managed by Sirberus")
+)
+
+var (
+	unitNamePattern        = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.@-]*\.service$`)
+	environmentNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+var restartPolicies = map[string]bool{
+	"no":          true,
+	"on-success":  true,
+	"on-failure":  true,
+	"on-abnormal": true,
+	"on-watchdog": true,
+	"on-abort":    true,
+	"always":      true,
+}
+
+// CreateUnit renders a unit spec to a service (and optional timer) in /etc/systemd/system,
+// verifies it, reloads the daemon and optionally enables and starts it. If enabling or
+// queueing the start fails, the created files are removed again so the request can be retried.
+func (s *SystemdService) CreateUnit(spec types.SystemdUnitSpec) (*types.SystemdUnitCreated, error) {
+	if !strings.HasSuffix(spec.Name, ".service") {
+		spec.Name += ".service"
+	}
+
+	serviceContent, err := renderServiceUnit(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{spec.Name: serviceContent}
+	activate := spec.Name
+	if spec.Timer != nil {
+		timerName := strings.TrimSuffix(spec.Name, ".service") + ".timer"
+		files[timerName] = renderTimerUnit(spec)
+		activate = timerName
+	}
+
+	s.overrideMu.Lock()
+	err = s.writeUnitFiles(files)
+	s.overrideMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	created := &types.SystemdUnitCreated{Name: spec.Name}
+
+	if spec.Enable {
+		if _, err := s.EnableUnit(activate); err != nil {
+			s.rollbackCreatedUnit(activate, false, files)
+			return nil, err
+		}
+	}
+
+	if spec.Start {
+		job, err := s.StartUnit(activate, jobModeReplace)
+		if err != nil {
+			s.rollbackCreatedUnit(activate, spec.Enable, files)
+			return nil, err
+		}
+		created.Job = job
+	}
+
+	return created, nil
+}
+
+// rollbackCreatedUnit disables and removes the files of a unit whose creation failed after
+// they were written. Failures are only logged since the original error is returned.
+func (s *SystemdService) rollbackCreatedUnit(activate string, enabled bool, files map[string]string) {
+	if enabled {
+		if _, err := s.DisableUnit(activate); err != nil {
+			s.logger.Error("failed to disable unit after failed creation",
+				"unit", activate,
+				"error", err)
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	s.overrideMu.Lock()
+	defer s.overrideMu.Unlock()
+
+	if err := removeUnitFiles(names); err != nil {
+		s.logger.Error("failed to remove unit files after failed creation",
+			"units", names,
+			"error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		s.logger.Error("failed to reload systemd after failed creation", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	if err := conn.ReloadContext(ctx); err != nil {
+		s.logger.Error("failed to reload systemd after failed creation", "error", err)
+	}
+}
+
+// writeUnitFiles writes new unit files, verifies them and reloads the daemon,
+// removing all of them again if any file exists already or verification fails
+func (s *SystemdService) writeUnitFiles(files map[string]string) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		path := filepath.Join(adminUnitDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%w: %s", ErrUnitExists, path)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	restore := func() error {
+		return removeUnitFiles(names)
+	}
+
+	for _, name := range names {
+		path := filepath.Join(adminUnitDir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			restore()
+			return fmt.Errorf("failed to write unit file %s: %w", path, err)
+		}
+	}
+
+	return s.verifyAndReload(restore, names...)
+}
+
+// removeUnitFiles removes unit files from /etc/systemd/system, ignoring missing ones
+func removeUnitFiles(names []string) error {
+	var errs []error
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(adminUnitDir, name)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteUnit stops, disables and removes a service created by Sirberus, including its timer
+func (s *SystemdService) DeleteUnit(name string) error {
+	if !unitNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: unit %q", ErrInvalidUnitFileName, name)
+	}
+
+	units := []string{strings.TrimSuffix(name, ".service") + ".timer", name}
+	paths := make([]string, 0, len(units))
+	for _, unit := range units {
+		path := filepath.Join(adminUnitDir, unit)
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read unit file %s: %w", path, err)
+		}
+		if !strings.HasPrefix(string(content), managedUnitHeader) {
+			return fmt.Errorf("%w: %s", ErrUnitNotManaged, path)
+		}
+
+		timeout := s.stopJobTimeout(unit)
+		job, err := s.StopUnit(unit, jobModeReplace)
+		if err != nil {
+			return err
+		}
+		result, err := s.waitForJobResult(job.ID, timeout)
+		if err != nil {
+			return err
+		}
+		if result != jobResultDone {
+			return fmt.Errorf("failed to stop unit %s: job result was %s", unit, result)
+		}
+		if _, err := s.DisableUnit(unit); err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("unit %s not found", name)
+	}
+
+	s.overrideMu.Lock()
+	defer s.overrideMu.Unlock()
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove unit file %s: %w", path, err)
+		}
+	}
+
+	// DisableUnit already reloaded the daemon, but it must see the removed files too
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.ReloadContext(ctx); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+
+	return nil
+}
+
+// renderServiceUnit renders a unit spec to the content of a service unit file
+func renderServiceUnit(spec types.SystemdUnitSpec) (string, error) {
+	if !unitNamePattern.MatchString(spec.Name) {
+		return "", fmt.Errorf("%w: invalid name %q", ErrInvalidUnitSpec, spec.Name)
+	}
+	if spec.ExecStart == "" {
+		return "", fmt.Errorf("%w: execStart is required", ErrInvalidUnitSpec)
+	}
+	if spec.Restart != "" && !restartPolicies[spec.Restart] {
+		return "", fmt.Errorf("%w: invalid restart policy %q", ErrInvalidUnitSpec, spec.Restart)
+	}
+
+	values := []string{spec.Description, spec.ExecStart, spec.User, spec.WorkingDirectory}
+	values = append(values, spec.WantedBy...)
+	for key, value := range spec.Environment {
+		if !environmentNamePattern.MatchString(key) {
+			return "", fmt.Errorf("%w: invalid environment variable name %q", ErrInvalidUnitSpec, key)
+		}
+		values = append(values, value)
+	}
+	if spec.Timer != nil {
+		if len(spec.Timer.OnCalendar) == 0 {
+			return "", fmt.Errorf("%w: timer requires at least one onCalendar expression", ErrInvalidUnitSpec)
+		}
+		values = append(values, spec.Timer.OnCalendar...)
+	}
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("%w: values must not contain line breaks", ErrInvalidUnitSpec)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, managedUnitHeader)
+	fmt.Fprintln(&b, "[Unit]")
+	if spec.Description != "" {
+		fmt.Fprintf(&b, "Description=%s\n", spec.Description)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "[Service]")
+	fmt.Fprintf(&b, "ExecStart=%s\n", spec.ExecStart)
+	if spec.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", spec.User)
+	}
+	if spec.WorkingDirectory != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", spec.WorkingDirectory)
+	}
+
+	keys := make([]string, 0, len(spec.Environment))
+	for key := range spec.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "Environment=%s\n", quoteUnitValue(key+"="+spec.Environment[key]))
+	}
+
+	if spec.Restart != "" {
+		fmt.Fprintf(&b, "Restart=%s\n", spec.Restart)
+	}
+
+	// Services activated by a timer are installed through the timer instead
+	if spec.Timer == nil {
+		wantedBy := spec.WantedBy
+		if len(wantedBy) == 0 {
+			wantedBy = []string{"multi-user.target"}
+		}
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "[Install]")
+		fmt.Fprintf(&b, "WantedBy=%s\n", strings.Join(wantedBy, " "))
+	}
+
+	return b.String(), nil
+}
+
+// renderTimerUnit renders the timer of a unit spec to the content of a timer unit file
+func renderTimerUnit(spec types.SystemdUnitSpec) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, managedUnitHeader)
+	fmt.Fprintln(&b, "[Unit]")
+	if spec.Description != "" {
+		fmt.Fprintf(&b, "Description=Timer for %s\n", spec.Description)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "[Timer]")
+	for _, expression := range spec.Timer.OnCalendar {
+		fmt.Fprintf(&b, "OnCalendar=%s\n", expression)
+	}
+	if spec.Timer.Persistent {
+		fmt.Fprintln(&b, "Persistent=true")
+	}
+	fmt.Fprintf(&b, "Unit=%s\n", spec.Name)
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "[Install]")
+	fmt.Fprintln(&b, "WantedBy=timers.target")
+
+	return b.String()
+}
+
+// quoteUnitValue quotes a value for a unit file setting, escaping quotes, backslashes and specifiers
+func quoteUnitValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "%", "%%")
+	return `"` + value + `"`
+}
+
+// stopJobTimeout returns how long stopping a unit may take: its TimeoutStopSec plus
+// stopJobTimeoutMargin. Units that cannot be asked, such as timers, only get the margin.
+func (s *SystemdService) stopJobTimeout(unit string) time.Duration {
+	if UnitType(unit) != "service" {
+		return stopJobTimeoutMargin
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultUnitTimeout)
+	defer cancel()
+
+	conn, err := s.newConnection(ctx)
+	if err != nil {
+		return jobWaitTimeout
+	}
+	defer conn.Close()
+
+	prop, err := conn.GetUnitTypePropertyContext(ctx, unit, "Service", "TimeoutStopUSec")
+	if err != nil {
+		s.logger.Warn("failed to get stop timeout of unit",
+			"unit", unit,
+			"error", err)
+		return jobWaitTimeout
+	}
+	usec, _ := prop.Value.Value().(uint64)
+	return stopTimeout(usec)
+}
+
+// stopTimeout adds stopJobTimeoutMargin to a TimeoutStopUSec value, capped at jobWaitTimeout
+// for units that wait forever
+func stopTimeout(timeoutStopUSec uint64) time.Duration {
+	if timeoutStopUSec >= uint64(jobWaitTimeout/time.Microsecond) {
+		return jobWaitTimeout
+	}
+	return time.Duration(timeoutStopUSec)*time.Microsecond + stopJobTimeoutMargin
+}
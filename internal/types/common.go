@@ -1,5 +1,10 @@
 package types
 
+import (
+	"regexp"
+	"time"
+)
+
 type Message struct {
 	Message string `json:"message"`
 } // @name Message
@@ -15,10 +20,67 @@ type ErrorResponse struct {
 
 // LogEntry represents a single log entry for streaming
 type LogEntry struct {
-	Content string `json:"content"`
-	Time    string `json:"time,omitempty"`
+	// Time of the entry (RFC3339 with microseconds)
+	Time string `json:"time,omitempty"`
+	// Syslog priority from 0 (emerg) to 7 (debug), omitted if unknown
+	Priority *int `json:"priority,omitempty"`
+	// ID of the process that logged the entry
+	PID int `json:"pid,omitempty"`
+	// Syslog identifier, usually the name of the program
+	SyslogIdentifier string `json:"syslogIdentifier,omitempty"`
+	// Log message
+	Message string `json:"message"`
+	// Unit that logged the entry
+	Unit string `json:"unit,omitempty"`
+	// Journal cursor of the entry
+	Cursor string `json:"cursor,omitempty"`
+	// ID of the boot the entry was logged in
+	BootID string `json:"bootId,omitempty"`
 } // @name LogEntry
 
+// MergedLogEntry represents an entry of a merged log stream tagged with its source
+type MergedLogEntry struct {
+	// Type of the source, "unit" or "container"
+	SourceType string `json:"sourceType"`
+	// Name of the unit or ID of the container the entry comes from
+	Source string `json:"source"`
+	LogEntry
+} // @name MergedLogEntry
+
+// LogPage represents a page of historical log entries in chronological order
+type LogPage struct {
+	Entries []LogEntry `json:"entries"`
+	// Cursor for the page of older entries, empty once the start of the log is reached
+	Before string `json:"before,omitempty"`
+	// Cursor for the page of newer entries
+	After string `json:"after,omitempty"`
+} // @name LogPage
+
+// LogFilter narrows down the entries of a log stream
+type LogFilter struct {
+	// Only entries written at or after this time
+	Since time.Time
+	// Only entries written at or before this time
+	Until time.Time
+	// Maximum syslog priority from 0 (emerg) to 7 (debug), journal only
+	Priority string
+	// Boot offset (e.g. "0" or "-1") or boot ID to read, journal only
+	Boot string
+	// Regular expression the message has to match
+	Grep *regexp.Regexp
+}
+
+// Matches reports whether a log message passes the grep filter
+func (f LogFilter) Matches(message string) bool {
+	return f.Grep == nil || f.Grep.MatchString(message)
+}
+
+// Follows reports whether new entries can still match the filter, which is not the
+// case once an end time or an earlier boot is selected
+func (f LogFilter) Follows() bool {
+	return f.Until.IsZero() && (f.Boot == "" || f.Boot == "0")
+}
+
 // SSEvent represents a server-sent event
 type SSEvent struct {
 	Type    string `json:"-"` // Not serialized, used for event type
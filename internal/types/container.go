@@ -100,6 +100,12 @@ type ContainerExecRequest struct {
 	Command string `json:"command"`
 } // @name ContainerExecRequest
 
+// ContainerKillRequest represents a request to send a signal to a container
+type ContainerKillRequest struct {
+	// Signal name (e.g., "SIGHUP", "SIGKILL")
+	Signal string `json:"signal"`
+} // @name ContainerKillRequest
+
 // ContainerList represents a list of containers
 type ContainerList struct {
 	// List of containers
@@ -5,7 +5,9 @@ type SystemdService struct {
 	Name string `json:"name"`
 	// Service description
 	Description string `json:"description"`
-	// Load state (e.g., "loaded", "not-found")
+	// Unit type (e.g., "service", "timer", "socket")
+	Type string `json:"type"`
+	// Load state (e.g., "loaded", "not-found", or "not-loaded" for installed units systemd has not loaded)
 	LoadState string `json:"loadState"`
 	// Active state (e.g., "active", "inactive")
 	ActiveState string `json:"activeState"`
@@ -17,6 +19,8 @@ type SystemdService struct {
 	MemoryUsage uint64 `json:"memoryUsage"`
 	// Uptime in seconds (time since service was started)
 	Uptime int64 `json:"uptime"`
+	// Unit file state (e.g., "enabled", "disabled", "masked", "static")
+	UnitFileState string `json:"unitFileState"`
 } // @name SystemdService
 
 type SystemdServiceDetails struct {
@@ -57,9 +61,395 @@ type SystemdServiceDetails struct {
 	FragmentPath string `json:"fragmentPath"`
 	// List of processes
 	Processes []string `json:"processes"`
+	// Current resource limits
+	Limits SystemdResourceLimits `json:"limits"`
+	// Sandboxing assessment, only set for services
+	Security *SystemdSecurityAssessment `json:"security,omitempty"`
+	SystemdFailureInfo
 } // @name SystemdServiceDetails
 
+// SystemdResourceLimits represents the resource limits of a unit, formatted like
+// the values accepted by SystemdPropertyChange ("infinity" when unlimited, empty when unset)
+type SystemdResourceLimits struct {
+	// CPU quota as a percentage of a single core (e.g., "50%")
+	CPUQuota string `json:"cpuQuota"`
+	// Hard memory limit in bytes
+	MemoryMax string `json:"memoryMax"`
+	// Memory throttling threshold in bytes
+	MemoryHigh string `json:"memoryHigh"`
+	// Maximum number of tasks
+	TasksMax string `json:"tasksMax"`
+	// IO weight (1-10000)
+	IOWeight string `json:"ioWeight"`
+	// CPUs the unit may run on (e.g., "0-3,6")
+	AllowedCPUs string `json:"allowedCPUs"`
+} // @name SystemdResourceLimits
+
+// SystemdPropertyChange represents a change to a single resource property of a unit
+type SystemdPropertyChange struct {
+	// Property name: CPUQuota, MemoryMax, MemoryHigh, TasksMax, IOWeight or AllowedCPUs
+	Name string `json:"name"`
+	// New value (e.g., "50%", "512M", "infinity", "0-3")
+	Value string `json:"value"`
+	// Keep the change across reboots; by default it is applied at runtime only, like systemctl set-property --runtime
+	Persistent bool `json:"persistent"`
+} // @name SystemdPropertyChange
+
+// SystemdSetPropertiesRequest represents a request to change resource properties of a unit
+type SystemdSetPropertiesRequest struct {
+	Changes []SystemdPropertyChange `json:"changes"`
+} // @name SystemdSetPropertiesRequest
+
+// SystemdTimer represents a systemd timer and its schedule
+type SystemdTimer struct {
+	// Timer unit name
+	Name string `json:"name"`
+	// Timer description
+	Description string `json:"description"`
+	// Active state (e.g., "active", "inactive")
+	ActiveState string `json:"activeState"`
+	// Unit activated when the timer elapses
+	Unit string `json:"unit"`
+	// Next time the timer elapses (RFC3339 format, empty if not scheduled)
+	NextElapse string `json:"nextElapse"`
+	// Last time the timer was triggered (RFC3339 format, empty if never triggered)
+	LastTrigger string `json:"lastTrigger"`
+	// OnCalendar expressions of the timer
+	OnCalendar []string `json:"onCalendar"`
+	// Whether missed runs are caught up after downtime
+	Persistent bool `json:"persistent"`
+} // @name SystemdTimer
+
+// SystemdTimerList represents a list of systemd timers
+type SystemdTimerList struct {
+	Timers []SystemdTimer `json:"timers"`
+	Count  int            `json:"count"`
+} // @name SystemdTimerList
+
+// SystemdUnitFileSource represents a single file contributing to the configuration of a unit
+type SystemdUnitFileSource struct {
+	// Path of the file
+	Path string `json:"path"`
+	// Origin of the file: "vendor" (/usr/lib, /lib), "admin" (/etc), "runtime" (/run) or "other"
+	Origin string `json:"origin"`
+	// Whether the file is a drop-in override rather than the main unit file
+	DropIn bool `json:"dropIn"`
+	// File content
+	Content string `json:"content"`
+} // @name SystemdUnitFileSource
+
+// SystemdUnitFile represents the effective configuration of a unit, like `systemctl cat`
+type SystemdUnitFile struct {
+	// Unit name
+	Name string `json:"name"`
+	// Main unit file followed by drop-ins in the order they are applied
+	Files []SystemdUnitFileSource `json:"files"`
+} // @name SystemdUnitFile
+
+// SystemdOverrideRequest represents a request to write a drop-in override for a unit
+type SystemdOverrideRequest struct {
+	// Content of the drop-in file
+	Content string `json:"content"`
+} // @name SystemdOverrideRequest
+
+// SystemdUnitSpec represents a structured definition of a custom service unit
+type SystemdUnitSpec struct {
+	// Service name (e.g., "myapp" or "myapp.service")
+	Name string `json:"name"`
+	// Service description
+	Description string `json:"description"`
+	// Command to run
+	ExecStart string `json:"execStart"`
+	// User to run the service as
+	User string `json:"user,omitempty"`
+	// Working directory of the service
+	WorkingDirectory string `json:"workingDirectory,omitempty"`
+	// Environment variables passed to the service
+	Environment map[string]string `json:"environment,omitempty"`
+	// Restart policy (e.g., "no", "on-failure", "always")
+	Restart string `json:"restart,omitempty"`
+	// Targets that pull in the service when enabled (defaults to multi-user.target)
+	WantedBy []string `json:"wantedBy,omitempty"`
+	// Optional timer that activates the service on a schedule
+	Timer *SystemdTimerSpec `json:"timer,omitempty"`
+	// Whether to enable the unit after creating it
+	Enable bool `json:"enable"`
+	// Whether to start the unit after creating it
+	Start bool `json:"start"`
+} // @name SystemdUnitSpec
+
+// SystemdUnitCreated represents a service created from a SystemdUnitSpec
+type SystemdUnitCreated struct {
+	// Name of the created service
+	Name string `json:"name"`
+	// Start job of the service or its timer, only set if it was started; follow it at /systemd/jobs/{id}
+	Job *SystemdJob `json:"job,omitempty"`
+} // @name SystemdUnitCreated
+
+// SystemdTimerSpec represents the schedule of a timer created alongside a custom service
+type SystemdTimerSpec struct {
+	// OnCalendar expressions (e.g., "daily", "*-*-* 03:00:00")
+	OnCalendar []string `json:"onCalendar"`
+	// Whether missed runs are caught up after downtime
+	Persistent bool `json:"persistent"`
+} // @name SystemdTimerSpec
+
+// SystemdRunRequest represents a request to run a command as a transient unit, like systemd-run
+type SystemdRunRequest struct {
+	// Command to execute and its arguments (e.g., ["sh", "-c", "echo a b"])
+	Command []string `json:"command"`
+	// Run the command in a transient scope attached to Sirberus instead of a transient service
+	Scope bool `json:"scope"`
+	// Memory limit (e.g., "512M", "2G" or a number of bytes)
+	MemoryMax string `json:"memoryMax,omitempty"`
+	// CPU quota as a percentage of a single core (e.g., "50%", "200%")
+	CPUQuota string `json:"cpuQuota,omitempty"`
+	// Maximum runtime in seconds before the unit is terminated
+	RuntimeMaxSec uint64 `json:"runtimeMaxSec,omitempty"`
+} // @name SystemdRunRequest
+
+// SystemdRunResult represents the final status of a command run as a transient unit
+type SystemdRunResult struct {
+	// Name of the transient unit
+	Unit string `json:"unit"`
+	// Unit result (e.g., "success", "exit-code", "signal", "timeout")
+	Result string `json:"result"`
+	// How the main process exited ("exited", "killed" or "dumped")
+	Code string `json:"code"`
+	// Exit status, or signal number if the process was killed
+	Status int32 `json:"status"`
+} // @name SystemdRunResult
+
+// SystemdJob represents a systemd job queued for a unit operation
+type SystemdJob struct {
+	// Numeric job ID
+	ID uint32 `json:"id"`
+	// Unit the job operates on
+	Unit string `json:"unit"`
+	// Job type (e.g., "start", "stop", "restart")
+	Type string `json:"type"`
+	// Job state ("waiting", "running" or "finished")
+	State string `json:"state"`
+	// Job result once finished (e.g., "done", "failed", "canceled", "timeout")
+	Result string `json:"result,omitempty"`
+} // @name SystemdJob
+
+// SystemdJobList represents a list of systemd jobs
+type SystemdJobList struct {
+	Jobs  []SystemdJob `json:"jobs"`
+	Count int          `json:"count"`
+} // @name SystemdJobList
+
+// SystemdUnitEvent represents a change of a unit's load, active or sub state
+type SystemdUnitEvent struct {
+	// Unit name
+	Unit string `json:"unit"`
+	// Load state (e.g., "loaded", "not-found")
+	LoadState string `json:"loadState"`
+	// Active state (e.g., "active", "inactive", "failed")
+	ActiveState string `json:"activeState"`
+	// Sub state (e.g., "running", "dead")
+	SubState string `json:"subState"`
+	// Time the change was received (RFC3339)
+	Timestamp string `json:"timestamp"`
+} // @name SystemdUnitEvent
+
+// SystemdDependencyNode represents a unit in a dependency graph
+type SystemdDependencyNode struct {
+	// Unit name
+	Name string `json:"name"`
+	// Load state (e.g., "loaded", "not-found")
+	LoadState string `json:"loadState"`
+	// Active state (e.g., "active", "inactive", "failed")
+	ActiveState string `json:"activeState"`
+	// Sub state (e.g., "running", "dead")
+	SubState string `json:"subState"`
+	// Distance from the root unit
+	Depth int `json:"depth"`
+} // @name SystemdDependencyNode
+
+// SystemdDependencyEdge represents a dependency between two units
+type SystemdDependencyEdge struct {
+	// Unit declaring the dependency
+	From string `json:"from"`
+	// Unit the dependency points to
+	To string `json:"to"`
+	// Dependency type (e.g., "Requires", "WantedBy", "After")
+	Type string `json:"type"`
+} // @name SystemdDependencyEdge
+
+// SystemdDependencyGraph represents the dependencies of a unit as a node/edge graph
+type SystemdDependencyGraph struct {
+	// Unit the graph was built from
+	Root string `json:"root"`
+	// Traversal direction: "forward" (what the unit needs) or "reverse" (what needs the unit)
+	Direction string `json:"direction"`
+	// Maximum traversal depth
+	Depth int `json:"depth"`
+	// Whether traversal stopped early because the graph grew too large
+	Truncated bool                    `json:"truncated"`
+	Nodes     []SystemdDependencyNode `json:"nodes"`
+	Edges     []SystemdDependencyEdge `json:"edges"`
+} // @name SystemdDependencyGraph
+
+// SystemdServiceList represents a list of systemd units, including installed units that are not loaded
 type SystemdServiceList struct {
 	Services []SystemdService `json:"services"`
 	Count    int              `json:"count"`
 } // @name SystemdServiceList
+
+// SystemdUnitFileChange represents a symlink change made while enabling, disabling, masking or unmasking a unit
+type SystemdUnitFileChange struct {
+	// Type of change (e.g., "symlink", "unlink")
+	Type string `json:"type"`
+	// Path of the symlink that was created or removed
+	Filename string `json:"filename"`
+	// Target of the symlink (empty for removals)
+	Destination string `json:"destination"`
+} // @name SystemdUnitFileChange
+
+// SystemdUnitFileChangeResponse represents the result of a unit file operation
+type SystemdUnitFileChangeResponse struct {
+	Message string `json:"message"`
+	// Symlink changes made by the operation
+	Changes []SystemdUnitFileChange `json:"changes"`
+} // @name SystemdUnitFileChangeResponse
+
+// SystemdKillRequest represents a request to send a signal to the processes of a unit
+type SystemdKillRequest struct {
+	// Signal name (e.g., "SIGHUP", "SIGKILL")
+	Signal string `json:"signal"`
+	// Processes to signal: "main", "control" or "all" (defaults to "all")
+	Target string `json:"target"`
+} // @name SystemdKillRequest
+
+// SystemdBoot represents a boot recorded in the journal
+type SystemdBoot struct {
+	// Offset to the current boot: 0 is the current boot, -1 the previous one
+	Index int `json:"index"`
+	// Boot ID
+	ID string `json:"id"`
+	// Time of the first journal entry of the boot (RFC3339 with microseconds)
+	FirstEntry string `json:"firstEntry"`
+	// Time of the last journal entry of the boot (RFC3339 with microseconds)
+	LastEntry string `json:"lastEntry"`
+} // @name SystemdBoot
+
+// SystemdBootList represents the boots recorded in the journal, oldest first
+type SystemdBootList struct {
+	Boots []SystemdBoot `json:"boots"`
+	Count int           `json:"count"`
+} // @name SystemdBootList
+
+// SystemdUnitTiming represents how long a unit took to activate, like a line of systemd-analyze blame
+type SystemdUnitTiming struct {
+	// Unit name
+	Name string `json:"name"`
+	// Time from leaving the inactive state until the unit became active, in milliseconds
+	ActivationTime int64 `json:"activationTime"`
+	// Time from leaving the inactive state until the main process of a service was started,
+	// in milliseconds; the rest of the activation time was spent waiting for it to become ready
+	ExecMainStartTime int64 `json:"execMainStartTime,omitempty"`
+	// Time since userspace started at which the unit became active, in milliseconds
+	ActivatedAt int64 `json:"activatedAt"`
+} // @name SystemdUnitTiming
+
+// SystemdCriticalChainNode represents a unit on the critical chain and the units it waited for.
+// It has no swag @name because swag does not rename recursive types consistently.
+type SystemdCriticalChainNode struct {
+	SystemdUnitTiming
+	// Units that became active last before this unit, usually one; units already shown
+	// elsewhere in the chain are not expanded again
+	Children []SystemdCriticalChainNode `json:"children"`
+}
+
+// SystemdBootAnalysis represents where the time of the boot went, like systemd-analyze blame and critical-chain
+type SystemdBootAnalysis struct {
+	// Whether the boot has finished
+	Finished bool `json:"finished"`
+	// Time from the start of userspace until the boot finished, in milliseconds; 0 while booting
+	UserspaceTime int64 `json:"userspaceTime"`
+	// Units that took time to activate, slowest first
+	Blame []SystemdUnitTiming `json:"blame"`
+	// Critical chain to the target
+	CriticalChain SystemdCriticalChainNode `json:"criticalChain"`
+} // @name SystemdBootAnalysis
+
+// SystemdSecurityCheck represents one assessed hardening setting, like a line of systemd-analyze security
+type SystemdSecurityCheck struct {
+	// Setting the check assesses (e.g. "ProtectSystem=" or "CapabilityBoundingSet=~CAP_SYS_ADMIN")
+	Name string `json:"name"`
+	// What the current value of the setting allows the service
+	Description string `json:"description"`
+	// Whether the setting is fully hardened
+	Passed bool `json:"passed"`
+	// Weight of the check in the exposure score
+	Weight int `json:"weight"`
+	// Exposure of the setting from 0.0 (hardened) to 1.0 (not hardened)
+	Exposure float64 `json:"exposure"`
+} // @name SystemdSecurityCheck
+
+// SystemdSecurityAssessment represents the sandboxing of a service, like systemd-analyze security
+type SystemdSecurityAssessment struct {
+	// Unit name
+	Unit string `json:"unit"`
+	// Overall exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed)
+	Exposure float64 `json:"exposure"`
+	// Rating of the exposure: PERFECT, OK, MEDIUM, EXPOSED, UNSAFE or DANGEROUS
+	Rating string `json:"rating"`
+	// Assessed settings, most exposing first
+	Checks []SystemdSecurityCheck `json:"checks"`
+} // @name SystemdSecurityAssessment
+
+// SystemdSecuritySummary represents the exposure of one service in the security report
+type SystemdSecuritySummary struct {
+	// Unit name
+	Unit string `json:"unit"`
+	// Active state of the service (e.g., "active", "inactive", "failed")
+	ActiveState string `json:"activeState"`
+	// Overall exposure from 0.0 (fully sandboxed) to 10.0 (not sandboxed)
+	Exposure float64 `json:"exposure"`
+	// Rating of the exposure: PERFECT, OK, MEDIUM, EXPOSED, UNSAFE or DANGEROUS
+	Rating string `json:"rating"`
+} // @name SystemdSecuritySummary
+
+// SystemdSecurityReport represents the exposure of all loaded services, most exposed first
+type SystemdSecurityReport struct {
+	Services []SystemdSecuritySummary `json:"services"`
+	Count    int                      `json:"count"`
+} // @name SystemdSecurityReport
+
+// SystemdFailureInfo represents the result of the last run of a unit and, if it failed, why
+type SystemdFailureInfo struct {
+	// Result of the last run (e.g., "success", "exit-code", "signal", "core-dump", "timeout", "start-limit-hit")
+	Result string `json:"result"`
+	// How the main process of a service ended: "exited", "killed" or "dumped"
+	ExecMainCode string `json:"execMainCode,omitempty"`
+	// Exit code of the main process, or the signal number if it was killed
+	ExecMainStatus int32 `json:"execMainStatus"`
+	// Number of automatic restarts of a service
+	NRestarts uint32 `json:"nRestarts"`
+	// Time the failed run stopped (RFC3339 format), only set if the last run did not succeed
+	FailedAt string `json:"failedAt,omitempty"`
+	// Last log entries of the failed run, including the messages systemd logged about it
+	FailureLogs []LogEntry `json:"failureLogs,omitempty"`
+} // @name SystemdFailureInfo
+
+// SystemdFailedUnit represents a failed unit and why it failed
+type SystemdFailedUnit struct {
+	// Unit name
+	Name string `json:"name"`
+	// Unit description
+	Description string `json:"description"`
+	// Unit type (e.g., "service", "timer", "mount")
+	Type string `json:"type"`
+	// Sub state of the unit (e.g., "failed")
+	SubState string `json:"subState"`
+	SystemdFailureInfo
+} // @name SystemdFailedUnit
+
+// SystemdFailedUnitList represents all failed units, most recent failure first
+type SystemdFailedUnitList struct {
+	Units []SystemdFailedUnit `json:"units"`
+	Count int                 `json:"count"`
+} // @name SystemdFailedUnitList
@@ -14,10 +14,21 @@ export * from './containerExecRequest';
 export * from './containerList';
 export * from './containerStatus';
 export * from './errorResponse';
+export * from './logEntry';
 export * from './message';
 export * from './mount';
 export * from './networkConfig';
+export * from './postSystemdNameRestartMode';
+export * from './postSystemdNameRestartParams';
+export * from './postSystemdNameRestartScope';
+export * from './postSystemdNameStartMode';
+export * from './postSystemdNameStartParams';
+export * from './postSystemdNameStartScope';
+export * from './postSystemdNameStopMode';
+export * from './postSystemdNameStopParams';
+export * from './postSystemdNameStopScope';
 export * from './sSEvent';
+export * from './systemdJob';
 export * from './systemdService';
 export * from './systemdServiceDetails';
 export * from './systemdServiceList';
@@ -0,0 +1,26 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export interface LogEntry {
+	/** ID of the boot the entry was logged in */
+	bootId?: string;
+	/** Journal cursor of the entry */
+	cursor?: string;
+	/** Log message */
+	message?: string;
+	/** ID of the process that logged the entry */
+	pid?: number;
+	/** Syslog priority from 0 (emerg) to 7 (debug), omitted if unknown */
+	priority?: number;
+	/** Syslog identifier, usually the name of the program */
+	syslogIdentifier?: string;
+	/** Time of the entry (RFC3339 with microseconds) */
+	time?: string;
+	/** Unit that logged the entry */
+	unit?: string;
+}
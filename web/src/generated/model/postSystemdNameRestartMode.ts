@@ -0,0 +1,17 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export type PostSystemdNameRestartMode = (typeof PostSystemdNameRestartMode)[keyof typeof PostSystemdNameRestartMode];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const PostSystemdNameRestartMode = {
+	replace: 'replace',
+	fail: 'fail',
+	isolate: 'isolate',
+	'ignore-dependencies': 'ignore-dependencies',
+} as const;
@@ -0,0 +1,24 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+import type { PostSystemdNameRestartMode } from './postSystemdNameRestartMode';
+import type { PostSystemdNameRestartScope } from './postSystemdNameRestartScope';
+
+export type PostSystemdNameRestartParams = {
+	/**
+	 * Job mode
+	 */
+	mode?: PostSystemdNameRestartMode;
+	/**
+	 * Systemd manager scope
+	 */
+	scope?: PostSystemdNameRestartScope;
+	/**
+	 * UID of the user manager (defaults to the UID Sirberus runs as)
+	 */
+	uid?: number;
+};
@@ -0,0 +1,24 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+import type { PostSystemdNameStartMode } from './postSystemdNameStartMode';
+import type { PostSystemdNameStartScope } from './postSystemdNameStartScope';
+
+export type PostSystemdNameStartParams = {
+	/**
+	 * Job mode
+	 */
+	mode?: PostSystemdNameStartMode;
+	/**
+	 * Systemd manager scope
+	 */
+	scope?: PostSystemdNameStartScope;
+	/**
+	 * UID of the user manager (defaults to the UID Sirberus runs as)
+	 */
+	uid?: number;
+};
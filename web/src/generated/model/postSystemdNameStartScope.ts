@@ -0,0 +1,15 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export type PostSystemdNameStartScope = (typeof PostSystemdNameStartScope)[keyof typeof PostSystemdNameStartScope];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const PostSystemdNameStartScope = {
+	system: 'system',
+	user: 'user',
+} as const;
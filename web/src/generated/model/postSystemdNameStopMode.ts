@@ -0,0 +1,17 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export type PostSystemdNameStopMode = (typeof PostSystemdNameStopMode)[keyof typeof PostSystemdNameStopMode];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const PostSystemdNameStopMode = {
+	replace: 'replace',
+	fail: 'fail',
+	isolate: 'isolate',
+	'ignore-dependencies': 'ignore-dependencies',
+} as const;
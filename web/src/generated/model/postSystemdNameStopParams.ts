@@ -0,0 +1,24 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+import type { PostSystemdNameStopMode } from './postSystemdNameStopMode';
+import type { PostSystemdNameStopScope } from './postSystemdNameStopScope';
+
+export type PostSystemdNameStopParams = {
+	/**
+	 * Job mode
+	 */
+	mode?: PostSystemdNameStopMode;
+	/**
+	 * Systemd manager scope
+	 */
+	scope?: PostSystemdNameStopScope;
+	/**
+	 * UID of the user manager (defaults to the UID Sirberus runs as)
+	 */
+	uid?: number;
+};
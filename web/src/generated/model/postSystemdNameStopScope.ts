@@ -0,0 +1,15 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export type PostSystemdNameStopScope = (typeof PostSystemdNameStopScope)[keyof typeof PostSystemdNameStopScope];
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const PostSystemdNameStopScope = {
+	system: 'system',
+	user: 'user',
+} as const;
@@ -0,0 +1,20 @@
+/**
+ * Generated by orval v7.7.0 🍺
+ * Do not edit manually.
+ * Sirberus API
+ * API for managing systemd services and containers
+ * OpenAPI spec version: 1.0
+ */
+
+export interface SystemdJob {
+	/** Numeric job ID */
+	id?: number;
+	/** Job result once finished (e.g., "done", "failed", "canceled", "timeout") */
+	result?: string;
+	/** Job state ("waiting", "running" or "finished") */
+	state?: string;
+	/** Job type (e.g., "start", "stop", "restart") */
+	type?: string;
+	/** Unit the job operates on */
+	unit?: string;
+}
@@ -23,7 +23,15 @@ import type {
 import * as axios from 'axios';
 import type { AxiosError, AxiosRequestConfig, AxiosResponse } from 'axios';
 
-import type { ErrorResponse, Message, SystemdServiceDetails, SystemdServiceList } from '.././model';
+import type {
+	ErrorResponse,
+	PostSystemdNameRestartParams,
+	PostSystemdNameStartParams,
+	PostSystemdNameStopParams,
+	SystemdJob,
+	SystemdServiceDetails,
+	SystemdServiceList,
+} from '.././model';
 
 /**
  * Get a list of all systemd services
@@ -230,20 +238,37 @@ export function useGetSystemdName<
 }
 
 /**
- * Restart a systemd service
+ * Restart a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
  * @summary Restart service
  */
-export const postSystemdNameRestart = (name: string, options?: AxiosRequestConfig): Promise<AxiosResponse<Message>> => {
-	return axios.default.post(`/systemd/${name}/restart`, undefined, options);
+export const postSystemdNameRestart = (
+	name: string,
+	params?: PostSystemdNameRestartParams,
+	options?: AxiosRequestConfig
+): Promise<AxiosResponse<SystemdJob>> => {
+	return axios.default.post(`/systemd/${name}/restart`, undefined, {
+		...options,
+		params: { ...params, ...options?.params },
+	});
 };
 
 export const getPostSystemdNameRestartMutationOptions = <
 	TError = AxiosError<ErrorResponse>,
 	TContext = unknown,
 >(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameRestart>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameRestart>>,
+		TError,
+		{ name: string; params?: PostSystemdNameRestartParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameRestart>>, TError, { name: string }, TContext> => {
+}): UseMutationOptions<
+	Awaited<ReturnType<typeof postSystemdNameRestart>>,
+	TError,
+	{ name: string; params?: PostSystemdNameRestartParams },
+	TContext
+> => {
 	const mutationKey = ['postSystemdNameRestart'];
 	const { mutation: mutationOptions, axios: axiosOptions } = options
 		? options.mutation && 'mutationKey' in options.mutation && options.mutation.mutationKey
@@ -251,10 +276,13 @@ export const getPostSystemdNameRestartMutationOptions = <
 			: { ...options, mutation: { ...options.mutation, mutationKey } }
 		: { mutation: { mutationKey }, axios: undefined };
 
-	const mutationFn: MutationFunction<Awaited<ReturnType<typeof postSystemdNameRestart>>, { name: string }> = props => {
-		const { name } = props ?? {};
+	const mutationFn: MutationFunction<
+		Awaited<ReturnType<typeof postSystemdNameRestart>>,
+		{ name: string; params?: PostSystemdNameRestartParams }
+	> = props => {
+		const { name, params } = props ?? {};
 
-		return postSystemdNameRestart(name, axiosOptions);
+		return postSystemdNameRestart(name, params, axiosOptions);
 	};
 
 	return { mutationFn, ...mutationOptions };
@@ -268,28 +296,55 @@ export type PostSystemdNameRestartMutationError = AxiosError<ErrorResponse>;
  * @summary Restart service
  */
 export const usePostSystemdNameRestart = <TError = AxiosError<ErrorResponse>, TContext = unknown>(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameRestart>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameRestart>>,
+		TError,
+		{ name: string; params?: PostSystemdNameRestartParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationResult<Awaited<ReturnType<typeof postSystemdNameRestart>>, TError, { name: string }, TContext> => {
+}): UseMutationResult<
+	Awaited<ReturnType<typeof postSystemdNameRestart>>,
+	TError,
+	{ name: string; params?: PostSystemdNameRestartParams },
+	TContext
+> => {
 	const mutationOptions = getPostSystemdNameRestartMutationOptions(options);
 
 	return useMutation(mutationOptions);
 };
 /**
- * Start a systemd service
+ * Start a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
  * @summary Start service
  */
-export const postSystemdNameStart = (name: string, options?: AxiosRequestConfig): Promise<AxiosResponse<Message>> => {
-	return axios.default.post(`/systemd/${name}/start`, undefined, options);
+export const postSystemdNameStart = (
+	name: string,
+	params?: PostSystemdNameStartParams,
+	options?: AxiosRequestConfig
+): Promise<AxiosResponse<SystemdJob>> => {
+	return axios.default.post(`/systemd/${name}/start`, undefined, {
+		...options,
+		params: { ...params, ...options?.params },
+	});
 };
 
 export const getPostSystemdNameStartMutationOptions = <
 	TError = AxiosError<ErrorResponse>,
 	TContext = unknown,
 >(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStart>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameStart>>,
+		TError,
+		{ name: string; params?: PostSystemdNameStartParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStart>>, TError, { name: string }, TContext> => {
+}): UseMutationOptions<
+	Awaited<ReturnType<typeof postSystemdNameStart>>,
+	TError,
+	{ name: string; params?: PostSystemdNameStartParams },
+	TContext
+> => {
 	const mutationKey = ['postSystemdNameStart'];
 	const { mutation: mutationOptions, axios: axiosOptions } = options
 		? options.mutation && 'mutationKey' in options.mutation && options.mutation.mutationKey
@@ -297,10 +352,13 @@ export const getPostSystemdNameStartMutationOptions = <
 			: { ...options, mutation: { ...options.mutation, mutationKey } }
 		: { mutation: { mutationKey }, axios: undefined };
 
-	const mutationFn: MutationFunction<Awaited<ReturnType<typeof postSystemdNameStart>>, { name: string }> = props => {
-		const { name } = props ?? {};
+	const mutationFn: MutationFunction<
+		Awaited<ReturnType<typeof postSystemdNameStart>>,
+		{ name: string; params?: PostSystemdNameStartParams }
+	> = props => {
+		const { name, params } = props ?? {};
 
-		return postSystemdNameStart(name, axiosOptions);
+		return postSystemdNameStart(name, params, axiosOptions);
 	};
 
 	return { mutationFn, ...mutationOptions };
@@ -314,28 +372,55 @@ export type PostSystemdNameStartMutationError = AxiosError<ErrorResponse>;
  * @summary Start service
  */
 export const usePostSystemdNameStart = <TError = AxiosError<ErrorResponse>, TContext = unknown>(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStart>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameStart>>,
+		TError,
+		{ name: string; params?: PostSystemdNameStartParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationResult<Awaited<ReturnType<typeof postSystemdNameStart>>, TError, { name: string }, TContext> => {
+}): UseMutationResult<
+	Awaited<ReturnType<typeof postSystemdNameStart>>,
+	TError,
+	{ name: string; params?: PostSystemdNameStartParams },
+	TContext
+> => {
 	const mutationOptions = getPostSystemdNameStartMutationOptions(options);
 
 	return useMutation(mutationOptions);
 };
 /**
- * Stop a systemd service
+ * Stop a systemd service. Returns the queued job, which can be followed at /systemd/jobs/{id}
  * @summary Stop service
  */
-export const postSystemdNameStop = (name: string, options?: AxiosRequestConfig): Promise<AxiosResponse<Message>> => {
-	return axios.default.post(`/systemd/${name}/stop`, undefined, options);
+export const postSystemdNameStop = (
+	name: string,
+	params?: PostSystemdNameStopParams,
+	options?: AxiosRequestConfig
+): Promise<AxiosResponse<SystemdJob>> => {
+	return axios.default.post(`/systemd/${name}/stop`, undefined, {
+		...options,
+		params: { ...params, ...options?.params },
+	});
 };
 
 export const getPostSystemdNameStopMutationOptions = <
 	TError = AxiosError<ErrorResponse>,
 	TContext = unknown,
 >(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStop>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameStop>>,
+		TError,
+		{ name: string; params?: PostSystemdNameStopParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStop>>, TError, { name: string }, TContext> => {
+}): UseMutationOptions<
+	Awaited<ReturnType<typeof postSystemdNameStop>>,
+	TError,
+	{ name: string; params?: PostSystemdNameStopParams },
+	TContext
+> => {
 	const mutationKey = ['postSystemdNameStop'];
 	const { mutation: mutationOptions, axios: axiosOptions } = options
 		? options.mutation && 'mutationKey' in options.mutation && options.mutation.mutationKey
@@ -343,10 +428,13 @@ export const getPostSystemdNameStopMutationOptions = <
 			: { ...options, mutation: { ...options.mutation, mutationKey } }
 		: { mutation: { mutationKey }, axios: undefined };
 
-	const mutationFn: MutationFunction<Awaited<ReturnType<typeof postSystemdNameStop>>, { name: string }> = props => {
-		const { name } = props ?? {};
+	const mutationFn: MutationFunction<
+		Awaited<ReturnType<typeof postSystemdNameStop>>,
+		{ name: string; params?: PostSystemdNameStopParams }
+	> = props => {
+		const { name, params } = props ?? {};
 
-		return postSystemdNameStop(name, axiosOptions);
+		return postSystemdNameStop(name, params, axiosOptions);
 	};
 
 	return { mutationFn, ...mutationOptions };
@@ -360,9 +448,19 @@ export type PostSystemdNameStopMutationError = AxiosError<ErrorResponse>;
  * @summary Stop service
  */
 export const usePostSystemdNameStop = <TError = AxiosError<ErrorResponse>, TContext = unknown>(options?: {
-	mutation?: UseMutationOptions<Awaited<ReturnType<typeof postSystemdNameStop>>, TError, { name: string }, TContext>;
+	mutation?: UseMutationOptions<
+		Awaited<ReturnType<typeof postSystemdNameStop>>,
+		TError,
+		{ name: string; params?: PostSystemdNameStopParams },
+		TContext
+	>;
 	axios?: AxiosRequestConfig;
-}): UseMutationResult<Awaited<ReturnType<typeof postSystemdNameStop>>, TError, { name: string }, TContext> => {
+}): UseMutationResult<
+	Awaited<ReturnType<typeof postSystemdNameStop>>,
+	TError,
+	{ name: string; params?: PostSystemdNameStopParams },
+	TContext
+> => {
 	const mutationOptions = getPostSystemdNameStopMutationOptions(options);
 
 	return useMutation(mutationOptions);
@@ -1,3 +1,4 @@
+import { LogEntry as JournalEntry } from '@/generated/model';
 import { useCallback, useEffect, useRef, useState } from 'react';
 import { toast } from 'sonner';
 
@@ -56,25 +57,15 @@ export function useSystemdLogs(serviceName: string, numLines: string) {
 			// Handle incoming log events
 			eventSource.addEventListener('output', (event: Event & { data?: string }) => {
 				try {
-					const logText = event.data || '';
-
-					// Parse the log entry (format: "YYYY-MM-DDTHH:MM:SS+00:00: Message content")
-					const timestampEndIndex = logText.indexOf(': ');
-					if (timestampEndIndex > 0) {
-						const timestamp = logText.substring(0, timestampEndIndex);
-						const message = logText.substring(timestampEndIndex + 2);
-
-						setLogs(prevLogs => [...prevLogs, { timestamp, message }]);
-					} else {
-						// If we can't parse the timestamp, just use the whole message
-						setLogs(prevLogs => [
-							...prevLogs,
-							{
-								timestamp: new Date().toISOString(),
-								message: logText,
-							},
-						]);
-					}
+					// Each event carries a structured journal entry
+					const entry = JSON.parse(event.data || '{}') as JournalEntry;
+					setLogs(prevLogs => [
+						...prevLogs,
+						{
+							timestamp: entry.time || new Date().toISOString(),
+							message: entry.message || '',
+						},
+					]);
 				} catch (err) {
 					console.error('Error parsing log entry:', err);
 				}